@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	rootCmd.AddCommand(checkNamesCmd)
+}
+
+// badName is an app or module whose directory name breaks Go conventions.
+type badName struct {
+	kind, dir string
+	problems  []string
+	// identifiers are the generated names the directory name leads to.
+	identifiers []string
+	suggestion  string
+}
+
+var checkNamesCmd = &cobra.Command{
+	Use:   "check-names",
+	Short: "Report app and module names that break Go package naming conventions",
+	Long: `Check the names of the apps under internal/ and of their modules against Go's
+package naming conventions: a single lower-case word, not shadowing a standard
+library package or one the templates generate. For each name that breaks them,
+the command lists the awkward identifiers it leads to, such as an import alias
+like httpmod or an environment prefix with underscores, and suggests a name.
+
+The command only reads the project. Directories matching a pattern in
+.grobignore are not scanned. It exits non-zero if any name breaks the
+conventions, so it can gate CI.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		bad, err := checkNames(projectRoot)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if len(bad) == 0 {
+			log.Println("All app and module names follow Go conventions.")
+			return
+		}
+
+		for _, b := range bad {
+			log.Printf("Problem: %s %s %s", b.kind, b.dir, strings.Join(b.problems, "; "))
+			log.Printf("  generates %s", strings.Join(b.identifiers, ", "))
+			log.Printf("  suggested name: %s", b.suggestion)
+		}
+		addNextStep("Rename them by hand: move the directory, update its package clause, imports, and registrations, then run 'grob doctor'. A 'grob rename-module' command to do this is planned.")
+		printNextSteps(cmd, args)
+		os.Exit(1)
+	},
+}
+
+// checkNames returns the apps and modules on disk whose names NameProblems
+// reports, apps first, in directory order.
+func checkNames(projectRoot string) ([]badName, error) {
+	ignore, err := utils.LoadIgnore(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := appsOnDisk(projectRoot, ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	var bad []badName
+	for _, app := range apps {
+		if problems := utils.NameProblems(app); problems != nil {
+			pkg, err := packageName(appMainPath(projectRoot, app))
+			if err != nil {
+				pkg = app
+			}
+			bad = append(bad, badName{
+				kind:     "app",
+				dir:      filepath.Join("internal", app),
+				problems: problems,
+				identifiers: []string{
+					"package " + pkg,
+					"main file " + app + "_main.go",
+					"environment prefix " + utils.EnvPrefix(app) + "_",
+				},
+				suggestion: utils.SuggestName(app),
+			})
+		}
+	}
+
+	for _, app := range apps {
+		modules, err := modulesOnDisk(projectRoot, app, ignore)
+		if err != nil {
+			return nil, err
+		}
+		for _, modulePath := range modules {
+			dir := filepath.Dir(modulePath)
+			name := filepath.Base(dir)
+			problems := utils.NameProblems(name)
+			if problems == nil {
+				continue
+			}
+			pkg, err := packageName(modulePath)
+			if err != nil {
+				pkg = name
+			}
+			typ := utils.FindModuleType(modulePath)
+			rel, err := filepath.Rel(projectRoot, dir)
+			if err != nil {
+				return nil, err
+			}
+			bad = append(bad, badName{
+				kind:     "module",
+				dir:      rel,
+				problems: problems,
+				identifiers: []string{
+					"package " + pkg,
+					fmt.Sprintf("import name %s in %s_main.go", utils.ModuleImportName(pkg), app),
+					fmt.Sprintf("types %sModule, %sController", typ, typ),
+				},
+				suggestion: utils.SuggestName(name),
+			})
+		}
+	}
+	return bad, nil
+}
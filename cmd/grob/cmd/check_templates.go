@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+)
+
+func init() {
+	rootCmd.AddCommand(checkTemplatesCmd)
+}
+
+// checkTemplatesCmd is a maintainer tool: it renders every built-in template
+// with sample data and verifies that the generated Go code parses.
+var checkTemplatesCmd = &cobra.Command{
+	Use:    "check-templates",
+	Short:  "Verify that all built-in templates render valid code",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := templates.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("All %d templates are valid.", len(templates.Registry))
+	},
+}
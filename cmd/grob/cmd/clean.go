@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	cleanGenerated bool
+	cleanYes       bool
+)
+
+// artifactPatterns are the file patterns of build output that clean removes:
+// binaries, plugins, test binaries, and test output, as listed in the .gitignore
+// of new projects. Only these are matched, whatever the project's .gitignore
+// says, since it may also list hand-written files such as .env.
+var artifactPatterns = []string{"*.exe", "*.exe~", "*.dll", "*.so", "*.dylib", "*.test", "*.out"}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanGenerated, "generated", false, "also remove Go files carrying the grob generated-code banner")
+	cleanCmd.Flags().BoolVarP(&cleanYes, "yes", "y", false, "remove files without asking for confirmation")
+	rootCmd.AddCommand(cleanCmd)
+}
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove build artifacts (and optionally grob-generated files) from a project",
+	Long: `Remove build artifacts from a project: files matching ` + strings.Join(artifactPatterns, ", ") + `.
+With --generated, Go files starting with the "` + utils.GeneratedBanner + `" banner,
+which grob regenerates, are removed as well. Other files are never touched, even
+if the project's .gitignore lists them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		targets, err := findCleanTargets(projectRoot, artifactPatterns, cleanGenerated)
+		if err != nil {
+			log.Fatalf("Failed to scan project: %v", err)
+		}
+		if len(targets) == 0 {
+			log.Println("Nothing to clean.")
+			return
+		}
+
+		log.Println("The following files will be removed:")
+		for _, t := range targets {
+			rel, _ := filepath.Rel(projectRoot, t)
+			log.Printf("  %s", rel)
+		}
+
+		if !cleanYes && !confirm(fmt.Sprintf("Remove %d file(s)?", len(targets))) {
+			log.Println("Aborted.")
+			return
+		}
+
+		for _, t := range targets {
+			if err := os.Remove(t); err != nil {
+				log.Fatalf("Failed to remove %s: %v", t, err)
+			}
+		}
+		log.Printf("Removed %d file(s).", len(targets))
+	},
+}
+
+// findCleanTargets walks the project and returns the files that clean should remove.
+func findCleanTargets(projectRoot string, patterns []string, generated bool) ([]string, error) {
+	var targets []string
+	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != projectRoot && (name == ".git" || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		if matchesAny(rel, patterns) {
+			targets = append(targets, path)
+			return nil
+		}
+		if generated && filepath.Ext(path) == ".go" {
+			ok, err := hasGeneratedBanner(path)
+			if err != nil {
+				return err
+			}
+			if ok {
+				targets = append(targets, path)
+			}
+		}
+		return nil
+	})
+	return targets, err
+}
+
+// matchesAny reports whether the file name of a project-relative path matches
+// one of the patterns.
+func matchesAny(rel string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGeneratedBanner reports whether a file starts with the grob generated-code banner.
+func hasGeneratedBanner(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	head := make([]byte, len(utils.GeneratedBanner))
+	n, _ := f.Read(head)
+	return bytes.Equal(head[:n], []byte(utils.GeneratedBanner)), nil
+}
+
+// confirm asks a yes/no question on stdin and reports whether the answer was yes.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func TestFindCleanTargets(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"api.exe":                  "binary",
+		"cover.out":                "mode: set",
+		"internal/api/api.test":    "binary",
+		"pkg/config/config.go":     "// Code generated by grob generate env-config. DO NOT EDIT.\n\npackage config\n",
+		"pkg/config/load.go":       "package config\n",
+		"internal/api/api_main.go": "package api\n\n// Code generated by grob\n",
+		".env":                     "DATABASE_URL=postgres://",
+		"README.md":                utils.GeneratedBanner + "\n",
+		"vendor/x/x.test":          "binary",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		generated bool
+		want      []string
+	}{
+		{false, []string{"api.exe", "cover.out", "internal/api/api.test"}},
+		{true, []string{"api.exe", "cover.out", "internal/api/api.test", "pkg/config/config.go"}},
+	}
+	for _, tt := range tests {
+		targets, err := findCleanTargets(root, artifactPatterns, tt.generated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, path := range targets {
+			rel, _ := filepath.Rel(root, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("findCleanTargets(generated=%t) = %v; want %v", tt.generated, got, tt.want)
+		}
+	}
+}
+
+// TestGeneratedBanner checks that the files grob regenerates carry the banner
+// clean --generated looks for.
+func TestGeneratedBanner(t *testing.T) {
+	if !strings.HasPrefix(templates.EnvConfigTmpl, utils.GeneratedBanner) {
+		t.Errorf("EnvConfigTmpl does not start with %q", utils.GeneratedBanner)
+	}
+}
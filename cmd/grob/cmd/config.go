@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and set machine-wide defaults in ~/.grob/config.yaml",
+	Long: `View and set defaults that apply to every project on this machine, stored in
+~/.grob/config.yaml. A project's .grobrc overrides them: get and list show the
+value in effect in the current directory and where it comes from.
+
+Keys:
+` + configKeyHelp(),
+}
+
+var configGetCmd = &cobra.Command{
+	Use:     "get [key]",
+	Short:   "Print the value of a setting in effect in the current directory",
+	Example: `  grob config get go-version`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, err := utils.LookupConfigKey(args[0])
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		value, _ := effectiveConfigValue(key, loadConfigLayers())
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a default in ~/.grob/config.yaml",
+	Example: `  grob config set go-version 1.22
+  grob config set author "Acme Inc."`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateGlobalConfig(args[0], args[1])
+		log.Printf("Set %s to %q.", args[0], args[1])
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:     "unset [key]",
+	Short:   "Remove a default from ~/.grob/config.yaml",
+	Example: `  grob config unset license`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateGlobalConfig(args[0], "")
+		log.Printf("Unset %s.", args[0])
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting in effect in the current directory and where it comes from",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		layers := loadConfigLayers()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, key := range utils.ConfigKeys {
+			value, source := effectiveConfigValue(key, layers)
+			if value == "" {
+				value = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t(%s)\n", key.Name, value, source)
+		}
+		w.Flush()
+	},
+}
+
+// configLayers holds the global config and, inside a project, its .grobrc.
+type configLayers struct {
+	global  *utils.Config
+	project *utils.Config
+}
+
+func loadConfigLayers() configLayers {
+	global, err := utils.LoadGlobalConfig()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	layers := configLayers{global: global}
+	if projectRoot, err := findProjectRoot(); err == nil {
+		if layers.project, err = utils.LoadProjectConfig(projectRoot); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	return layers
+}
+
+// effectiveConfigValue returns the value of key in effect and where it is set:
+// the project's .grobrc, the global config, or grob's default.
+func effectiveConfigValue(key utils.ConfigKey, layers configLayers) (value, source string) {
+	if layers.project != nil {
+		if v := key.Get(layers.project); v != "" {
+			return v, utils.ConfigFileName
+		}
+	}
+	if v := key.Get(layers.global); v != "" {
+		path, _ := utils.GlobalConfigPath()
+		return v, path
+	}
+	return key.Default, "default"
+}
+
+// configValue returns a key's value in cfg, or its default.
+func configValue(cfg *utils.Config, name string) string {
+	key, err := utils.LookupConfigKey(name)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if v := key.Get(cfg); v != "" {
+		return v
+	}
+	return key.Default
+}
+
+// updateGlobalConfig sets, or with an empty value unsets, a key in the global config.
+func updateGlobalConfig(name, value string) {
+	key, err := utils.LookupConfigKey(name)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	cfg, err := utils.LoadGlobalConfig()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := key.Set(cfg, value); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := utils.SaveGlobalConfig(cfg); err != nil {
+		log.Fatalf("Failed to save the config: %v", err)
+	}
+}
+
+// configKeyHelp describes the known keys for the command's help.
+func configKeyHelp() string {
+	help := ""
+	for _, key := range utils.ConfigKeys {
+		help += fmt.Sprintf("  %-18s %s", key.Name, key.Description)
+		if key.Default != "" {
+			help += fmt.Sprintf(" (default %s)", key.Default)
+		}
+		help += "\n"
+	}
+	return help
+}
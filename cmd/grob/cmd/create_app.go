@@ -1,17 +1,67 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/yuliussmayoru/grob-cli/internal/templates"
 	"github.com/yuliussmayoru/grob-cli/internal/utils"
 )
 
+var (
+	appNoRegister bool
+	appRegenMain  bool
+	appType       string
+	appQueue      string
+	appPort       int
+	appGRPCPort   int
+	appHealthPort int
+
+	appReadTimeout  time.Duration
+	appWriteTimeout time.Duration
+	appIdleTimeout  time.Duration
+	appInterval     time.Duration
+	appCopyFrom     string
+)
+
+// frameworkModule is the module path of grob-framework.
+const frameworkModule = "github.com/yuliussmayoru/grob-framework"
+
+// workerQueue describes a message broker a worker app can consume from.
+type workerQueue struct {
+	tmpl    string
+	module  string
+	version string
+}
+
+// workerQueues maps each supported --queue value to its consumer template and client library.
+var workerQueues = map[string]workerQueue{
+	"nats":     {templates.NatsConsumerTmpl, "github.com/nats-io/nats.go", "v1.37.0"},
+	"kafka":    {templates.KafkaConsumerTmpl, "github.com/segmentio/kafka-go", "v0.4.47"},
+	"rabbitmq": {templates.RabbitMQConsumerTmpl, "github.com/rabbitmq/amqp091-go", "v1.10.0"},
+}
+
 func init() {
+	createAppCmd.Flags().BoolVar(&appNoRegister, "no-register", false, "generate the app without registering it in internal/main.go")
+	createAppCmd.Flags().BoolVar(&appRegenMain, "regenerate-main", false, "regenerate internal/main.go without prompting if its apps map cannot be found")
+	createAppCmd.Flags().StringVar(&appCopyFrom, "copy-from", "", "clone an existing app's files and modules, renaming it throughout")
+	createAppCmd.Flags().StringVar(&appType, "type", "http", "type of app to generate: http, worker, job, scheduler, or cli")
+	createAppCmd.Flags().StringVar(&appQueue, "queue", "nats", "message broker a worker app consumes from: nats, kafka, or rabbitmq")
+	createAppCmd.Flags().IntVar(&appPort, "port", 0, fmt.Sprintf("port the HTTP server listens on (default: the first port from %d no other app uses)", utils.FirstAppPort))
+	createAppCmd.Flags().IntVar(&appHealthPort, "health-port", 0, "serve health checks, metrics, and pprof on this port, on a management listener apart from the public routes")
+	createAppCmd.Flags().IntVar(&appGRPCPort, "grpc-port", 0, "also serve gRPC on this port, next to HTTP, with the *grpc.Server provided to the container")
+	createAppCmd.Flags().DurationVar(&appReadTimeout, "read-timeout", 15*time.Second, "default HTTP server read timeout")
+	createAppCmd.Flags().DurationVar(&appWriteTimeout, "write-timeout", 15*time.Second, "default HTTP server write timeout")
+	createAppCmd.Flags().DurationVar(&appInterval, "interval", time.Minute, "default run interval of a job app, or of a scheduler app's example job")
+	createAppCmd.Flags().DurationVar(&appIdleTimeout, "idle-timeout", 60*time.Second, "default HTTP server idle (keep-alive) timeout")
 	rootCmd.AddCommand(createAppCmd)
 }
 
@@ -23,45 +73,409 @@ var createAppCmd = &cobra.Command{
 		appName := args[0]
 		log.Printf("Creating new application: %s", appName)
 
-		projectRoot, err := utils.FindProjectRoot()
+		projectRoot, err := findProjectRoot()
 		if err != nil {
 			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
 		}
-		projectName := utils.GetProjectName(projectRoot)
 
-		appDir := filepath.Join(projectRoot, "internal", appName)
-		if err := os.Mkdir(appDir, 0755); err != nil {
-			log.Fatalf("Failed to create app directory: %v", err)
+		files, err := createApp(projectRoot, appName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportCreated(projectRoot, files)
+	},
+}
+
+// createApp generates a new app under internal/ and registers it in internal/main.go.
+// It returns the files it wrote, including those written before an error.
+func createApp(projectRoot, appName string) ([]string, error) {
+	data := utils.TemplateData(projectRoot)
+	data["AppName"] = appName
+	data["EnvPrefix"] = utils.EnvPrefix(appName)
+	data["ReadTimeout"] = durationExpr(appReadTimeout)
+	data["WriteTimeout"] = durationExpr(appWriteTimeout)
+	data["IdleTimeout"] = durationExpr(appIdleTimeout)
+	data["GRPCPort"] = ""
+	data["HealthPort"] = ""
+	projectName := data["ProjectName"]
+
+	port, err := appListenPort(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	data["Port"] = strconv.Itoa(port)
+
+	var files createdFiles
+	if appCopyFrom != "" {
+		copied, err := utils.CloneApp(projectRoot, projectName, appCopyFrom, appName)
+		files.add(copied...)
+		if err != nil {
+			return files, fmt.Errorf("failed to copy app '%s': %w", appCopyFrom, err)
+		}
+		log.Printf("Copied app '%s' to '%s'.", appCopyFrom, appName)
+		if err := setClonedPort(appMainPath(projectRoot, appName), port, projectName); err != nil {
+			return files, err
 		}
+		if err := createAppModule(projectRoot, appName, data, &files); err != nil {
+			return files, err
+		}
+		return files, registerApp(projectRoot, projectName, appName, &files)
+	}
 
-		coreDir := filepath.Join(appDir, "core")
-		if err := os.Mkdir(coreDir, 0755); err != nil {
-			log.Fatalf("Failed to create app core directory: %v", err)
+	var queue workerQueue
+	switch appType {
+	case "http":
+	case "worker":
+		var ok bool
+		if queue, ok = workerQueues[appQueue]; !ok {
+			return nil, fmt.Errorf("unknown queue %q: use nats, kafka, or rabbitmq", appQueue)
+		}
+		data["Queue"] = appQueue
+	case "job", "scheduler":
+		if appInterval <= 0 {
+			return nil, fmt.Errorf("invalid interval %s: it must be positive", appInterval)
+		}
+		data["JobInterval"] = durationExpr(appInterval)
+		data["JobIntervalText"] = appInterval.String()
+	case "cli":
+		cfg, err := utils.LoadConfig(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+		data["CLIStandalone"] = ""
+		if cfg.Binaries() {
+			data["CLIStandalone"] = "true"
+		}
+	default:
+		return nil, fmt.Errorf("unknown app type %q: use http, worker, job, scheduler, or cli", appType)
+	}
+	if appGRPCPort != 0 {
+		switch {
+		case appType != "http":
+			return nil, fmt.Errorf("--grpc-port is only supported for http apps")
+		case appGRPCPort < 1 || appGRPCPort > 65535:
+			return nil, fmt.Errorf("invalid gRPC port %d", appGRPCPort)
+		case appGRPCPort == port:
+			return nil, fmt.Errorf("--grpc-port must differ from the HTTP port %d", port)
+		}
+		if used, err := utils.UsedPorts(projectRoot); err == nil && used[appGRPCPort] != "" {
+			log.Printf("Warning: port %d is already used by app '%s'; the two apps cannot run at the same time.", appGRPCPort, used[appGRPCPort])
+		}
+		data["GRPCPort"] = strconv.Itoa(appGRPCPort)
+	}
+	if appHealthPort != 0 {
+		switch {
+		case appType != "http":
+			return nil, fmt.Errorf("--health-port is only supported for http apps")
+		case appHealthPort < 1 || appHealthPort > 65535:
+			return nil, fmt.Errorf("invalid health port %d", appHealthPort)
+		case appHealthPort == port:
+			return nil, fmt.Errorf("--health-port must differ from the HTTP port %d", port)
+		case appHealthPort == appGRPCPort:
+			return nil, fmt.Errorf("--health-port must differ from --grpc-port")
+		}
+		if used, err := utils.UsedPorts(projectRoot); err == nil && used[appHealthPort] != "" {
+			log.Printf("Warning: port %d is already used by app '%s'; the two apps cannot run at the same time.", appHealthPort, used[appHealthPort])
 		}
+		data["HealthPort"] = strconv.Itoa(appHealthPort)
+	}
 
-		coreFileContent := `package core
-import "github.com/yuliussmayoru/grob-framework/pkg/framework"
+	appDir := filepath.Join(projectRoot, "internal", appName)
+	if err := os.Mkdir(appDir, utils.DirMode); err != nil {
+		return nil, fmt.Errorf("failed to create app directory: %w", err)
+	}
+	if err := createAppModule(projectRoot, appName, data, &files); err != nil {
+		return files, err
+	}
+	appMainPath := filepath.Join(appDir, fmt.Sprintf("%s_main.go", appName))
 
-// Re-export the framework types to make them local to the app
-type App = framework.App
-type Module = framework.Module
-var New = framework.New
-`
-		if err := os.WriteFile(filepath.Join(coreDir, "core.go"), []byte(coreFileContent), 0644); err != nil {
-			log.Fatalf("Failed to create core.go: %v", err)
+	if appType == "worker" {
+		if err := files.tmpl(appMainPath, templates.WorkerMainTmpl, data); err != nil {
+			return files, err
 		}
+		if err := files.tmpl(filepath.Join(appDir, "consumer.go"), queue.tmpl, data); err != nil {
+			return files, err
+		}
+		if err := utils.AddRequire(projectRoot, queue.module, queue.version); err != nil {
+			return files, fmt.Errorf("failed to update go.mod: %w", err)
+		}
+		return files, registerApp(projectRoot, projectName, appName, &files)
+	}
+
+	if appType == "job" {
+		if err := files.tmpl(appMainPath, templates.JobMainTmpl, data); err != nil {
+			return files, err
+		}
+		return files, registerApp(projectRoot, projectName, appName, &files)
+	}
 
-		appMainPath := filepath.Join(appDir, fmt.Sprintf("%s_main.go", appName))
-		utils.CreateFileFromTmpl(appMainPath, templates.AppMainTmpl, map[string]string{
+	if appType == "scheduler" {
+		if err := files.tmpl(appMainPath, templates.SchedulerMainTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(appDir, "registry.go"), templates.SchedulerRegistryTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(appDir, "example_job.go"), templates.SchedulerExampleJobTmpl, data); err != nil {
+			return files, err
+		}
+		if err := utils.AddRequire(projectRoot, "github.com/robfig/cron/v3", "v3.0.1"); err != nil {
+			return files, fmt.Errorf("failed to update go.mod: %w", err)
+		}
+		addNextStep("Add each periodic task as a file in internal/%s that registers a Job from its init function, and run 'go mod tidy' to download robfig/cron.", appName)
+		return files, registerApp(projectRoot, projectName, appName, &files)
+	}
+
+	if appType == "cli" {
+		return files, createCLIApp(projectRoot, appMainPath, data, &files)
+	}
+
+	coreDir := filepath.Join(appDir, "core")
+	if err := os.Mkdir(coreDir, utils.DirMode); err != nil {
+		return files, fmt.Errorf("failed to create app core directory: %w", err)
+	}
+
+	if err := files.tmpl(filepath.Join(coreDir, "core.go"), templates.CoreTmpl, data); err != nil {
+		return files, err
+	}
+	if appGRPCPort != 0 {
+		if err := createGRPCServerPackage(projectRoot, appDir, data, &files); err != nil {
+			return files, err
+		}
+	}
+	if appHealthPort != 0 {
+		if err := utils.AddRequire(projectRoot, "golang.org/x/sync", "v0.8.0"); err != nil {
+			return files, fmt.Errorf("failed to update go.mod: %w", err)
+		}
+		addNextStep("Mount health checks, metrics, and pprof on the management port %d with 'grob generate healthcheck %s', 'grob generate metrics %s', and 'grob generate pprof %s'.", appHealthPort, appName, appName, appName)
+	}
+	if err := files.tmpl(appMainPath, templates.AppMainTmpl, data); err != nil {
+		return files, err
+	}
+	return files, registerApp(projectRoot, projectName, appName, &files)
+}
+
+// createCLIApp writes a command-line app and registers it. Unless the project
+// builds a binary per app, internal/main.go must run an app named by its first
+// argument alone, so running the command does not start the other apps too.
+func createCLIApp(projectRoot, appMainPath string, data map[string]string, files *createdFiles) error {
+	appName, projectName := data["AppName"], data["ProjectName"]
+	if err := files.tmpl(appMainPath, templates.CLIMainTmpl, data); err != nil {
+		return err
+	}
+	if err := utils.AddRequire(projectRoot, "github.com/spf13/cobra", "v1.9.1"); err != nil {
+		return fmt.Errorf("failed to update go.mod: %w", err)
+	}
+	if err := registerApp(projectRoot, projectName, appName, files); err != nil {
+		return err
+	}
+	if data["CLIStandalone"] != "" {
+		addNextStep("Run 'go mod tidy', then try it with 'go run ./cmd/%s run --help'.", appName)
+		return nil
+	}
+
+	internalMainPath := filepath.Join(projectRoot, "internal", "main.go")
+	ok, err := utils.AddAppSelectionToInternalMain(internalMainPath)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", internalMainPath, err)
+	}
+	if !ok {
+		addNextStep("Make %s run an app named by its first argument alone, before starting every app:\n"+
+			"  if len(os.Args) > 1 {\n    if app, ok := apps[os.Args[1]]; ok {\n      app.Run()\n      return\n    }\n  }", internalMainPath)
+	}
+	addNextStep("Run 'go mod tidy', then try it with 'go run ./internal %s run --help'.", appName)
+	return nil
+}
+
+// appListenPort returns the port a new app's HTTP server listens on: --port,
+// with a warning if another app already listens on it, or else the first port
+// from utils.FirstAppPort that no app's main file uses.
+func appListenPort(projectRoot string) (int, error) {
+	used, err := utils.UsedPorts(projectRoot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the ports of existing apps: %w", err)
+	}
+	if appPort == 0 {
+		return utils.NextFreePort(used, appGRPCPort, appHealthPort), nil
+	}
+	if appPort < 1 || appPort > 65535 {
+		return 0, fmt.Errorf("invalid port %d", appPort)
+	}
+	if app, ok := used[appPort]; ok {
+		log.Printf("Warning: port %d is already used by app '%s'; the two apps cannot run at the same time.", appPort, app)
+	}
+	return appPort, nil
+}
+
+// setClonedPort makes an app copied with --copy-from listen on port instead of
+// the port of the app it was copied from.
+func setClonedPort(mainPath string, port int, projectName string) error {
+	ports, err := utils.MainPorts(mainPath)
+	if err != nil || len(ports) == 0 || ports[0] == port {
+		return err
+	}
+	old, new := strconv.Quote(fmt.Sprintf(":%d", ports[0])), strconv.Quote(fmt.Sprintf(":%d", port))
+	if _, err := utils.ReplaceInFile(mainPath, old, new, projectName); err != nil {
+		return fmt.Errorf("failed to set the port in %s: %w", mainPath, err)
+	}
+	return nil
+}
+
+// createAppModule makes an app of a workspace project a module of its own. It
+// writes the app's go.mod, requiring the same grob-framework as the project,
+// and adds the app to go.work and, replaced by its directory, to the project's
+// go.mod. Other projects are left alone.
+func createAppModule(projectRoot, appName string, data map[string]string, files *createdFiles) error {
+	cfg, err := utils.LoadConfig(projectRoot)
+	if err != nil {
+		return err
+	}
+	if !cfg.Workspace {
+		return nil
+	}
+	goVersion, err := utils.GoVersion(projectRoot)
+	if err != nil {
+		return err
+	}
+	version, replacement, err := utils.ModuleRequirement(projectRoot, frameworkModule)
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return fmt.Errorf("go.mod does not require %s", frameworkModule)
+	}
+	// Local replacements are relative to the project root, two levels up.
+	if strings.HasPrefix(replacement, "./") || strings.HasPrefix(replacement, "../") {
+		replacement = path.Join("../..", replacement)
+	}
+
+	moduleData := utils.TemplateData(projectRoot)
+	moduleData["AppName"] = appName
+	moduleData["GoVersion"] = goVersion
+	moduleData["FrameworkVersion"] = version
+	moduleData["FrameworkReplace"] = replacement
+	moduleData["LocalModuleVersion"] = utils.LocalModuleVersion
+	if err := files.tmpl(filepath.Join(projectRoot, "internal", appName, "go.mod"), templates.AppGoModTmpl, moduleData); err != nil {
+		return err
+	}
+
+	dir := "./internal/" + appName
+	if err := utils.AddWorkspaceUse(projectRoot, dir); err != nil {
+		return fmt.Errorf("failed to update %s: %w", utils.WorkFileName, err)
+	}
+	if err := utils.AddLocalModule(projectRoot, data["ProjectName"]+"/internal/"+appName, dir); err != nil {
+		return fmt.Errorf("failed to update go.mod: %w", err)
+	}
+	addNextStep("Run 'go mod tidy' in internal/%s as well as in the project root.", appName)
+	return nil
+}
+
+// createGRPCServerPackage adds the grpcserver package of an app that serves
+// gRPC next to HTTP, and requires the libraries the app's main uses for it.
+func createGRPCServerPackage(projectRoot, appDir string, data map[string]string, files *createdFiles) error {
+	dir := filepath.Join(appDir, "grpcserver")
+	if err := os.Mkdir(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create grpcserver package: %w", err)
+	}
+	if err := files.tmpl(filepath.Join(dir, "grpcserver.go"), templates.GRPCServerPkgTmpl, data); err != nil {
+		return err
+	}
+	for _, req := range [][2]string{
+		{"google.golang.org/grpc", "v1.58.3"},
+		{"golang.org/x/sync", "v0.8.0"},
+	} {
+		if err := utils.AddRequire(projectRoot, req[0], req[1]); err != nil {
+			return fmt.Errorf("failed to update go.mod: %w", err)
+		}
+	}
+	addNextStep("Run 'go mod tidy'. Modules created with --transport grpc or both are served on port %s.", data["GRPCPort"])
+	return nil
+}
+
+// registerApp adds the app to internal/main.go, or prints the snippet to add when --no-register is set.
+// In the binaries layout it generates cmd/<app>/main.go instead. Files it writes are added to files.
+func registerApp(projectRoot, projectName, appName string, files *createdFiles) error {
+	cfg, err := utils.LoadConfig(projectRoot)
+	if err != nil {
+		return err
+	}
+	if cfg.Binaries() {
+		if appNoRegister {
+			log.Printf("Application '%s' created.", appName)
+			addNextStep("Add cmd/%s/main.go to build it as a binary.", appName)
+			return nil
+		}
+		binDir := filepath.Join(projectRoot, "cmd", appName)
+		if err := os.MkdirAll(binDir, utils.DirMode); err != nil {
+			return fmt.Errorf("failed to create binary directory: %w", err)
+		}
+		err := files.tmpl(filepath.Join(binDir, "main.go"), templates.BinaryMainTmpl, map[string]string{
 			"ProjectName": projectName,
 			"AppName":     appName,
 		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Application '%s' created. Build it with: go build ./cmd/%s", appName, appName)
+		return nil
+	}
 
-		internalMainPath := filepath.Join(projectRoot, "internal", "main.go")
-		if err := utils.AddAppToInternalMain(internalMainPath, projectName, appName); err != nil {
-			log.Fatalf("Failed to auto-register app: %v", err)
+	if appNoRegister {
+		log.Printf("Application '%s' created.", appName)
+		addNextStep("Register it in internal/main.go:\n  import \"%s/internal/%s\"\n  apps := map[string]AppRunner{\"%s\": %s.App{}}", projectName, appName, appName, appName)
+		return nil
+	}
+
+	internalMainPath := filepath.Join(projectRoot, "internal", "main.go")
+	err = utils.AddAppToInternalMain(internalMainPath, projectName, appName)
+	if errors.Is(err, utils.ErrAppsMapNotFound) {
+		log.Printf("Warning: %v.", err)
+		if appRegenMain || confirm("Regenerate internal/main.go, keeping the apps it imports?") {
+			err = regenerateInternalMain(projectRoot, projectName, appName, files)
 		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to auto-register app: %w", err)
+	}
 
-		log.Printf("Application '%s' created and registered successfully.", appName)
-	},
+	log.Printf("Application '%s' created and registered successfully.", appName)
+	return nil
+}
+
+// regenerateInternalMain rewrites internal/main.go from InternalMainTmpl and
+// registers every app the old file imported, plus appName. The old file is kept
+// as internal/main.go.bak so hand-written changes can be merged back. Both are added to files.
+func regenerateInternalMain(projectRoot, projectName, appName string, files *createdFiles) error {
+	internalMainPath := filepath.Join(projectRoot, "internal", "main.go")
+	apps, err := utils.RegisteredApps(internalMainPath, projectName)
+	if err != nil {
+		return err
+	}
+
+	old, err := os.ReadFile(internalMainPath)
+	if err != nil {
+		return err
+	}
+	backupPath := internalMainPath + ".bak"
+	if err := utils.WriteFile(backupPath, old, utils.FileMode); err != nil {
+		return err
+	}
+	files.add(backupPath)
+	if err := files.tmpl(internalMainPath, templates.InternalMainTmpl, nil); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, app := range append(apps, appName) {
+		if seen[app] {
+			continue
+		}
+		seen[app] = true
+		if err := utils.AddAppToInternalMain(internalMainPath, projectName, app); err != nil {
+			return err
+		}
+		log.Printf("Registered '%s' in the regenerated internal/main.go.", app)
+	}
+	log.Printf("Previous internal/main.go saved as %s.", backupPath)
+	return nil
 }
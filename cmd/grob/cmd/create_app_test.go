@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newTestProject creates a project with the given module path in a temporary
+// directory, away from the user's global config, and returns its root. The
+// project uses the grob-framework stub in testdata/framework.
+func newTestProject(t *testing.T, projectName string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	framework, err := filepath.Abs(filepath.Join("testdata", "framework"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFrameworkReplace = framework
+	t.Cleanup(func() { newFrameworkReplace = "" })
+	projectRoot := filepath.Join(t.TempDir(), filepath.Base(projectName))
+	if _, err := newProject(projectName, projectRoot); err != nil {
+		t.Fatal(err)
+	}
+	return projectRoot
+}
+
+// TestCreateAppDomainModulePath builds a project whose module path has a
+// domain, so every import path the templates compose from it must resolve.
+func TestCreateAppDomainModulePath(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a generated project")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	projectRoot := newTestProject(t, "github.com/acme/shop")
+	if _, err := createApp(projectRoot, "api"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := createModule(projectRoot, "api", "users"); err != nil {
+		t.Fatal(err)
+	}
+
+	tidy := exec.Command("go", "mod", "tidy")
+	tidy.Dir = projectRoot
+	if out, err := tidy.CombinedOutput(); err != nil {
+		t.Skipf("dependencies are not available: %v\n%s", err, out)
+	}
+	build := exec.Command("go", "build", "./...")
+	build.Dir = projectRoot
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+}
@@ -2,16 +2,54 @@ package cmd
 
 import (
 	"fmt"
+	"go/token"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
 
 	"github.com/spf13/cobra"
 	"github.com/yuliussmayoru/grob-cli/internal/templates"
 	"github.com/yuliussmayoru/grob-cli/internal/utils"
 )
 
+var (
+	moduleTemplateDir string
+	moduleNoRegister  bool
+	moduleDeps        []string
+	moduleRespFormat  string
+	moduleDirStyle    string
+	moduleVars        []string
+	moduleTransport   string
+	moduleCtx         bool
+	moduleInterface   bool
+	moduleSubpackages bool
+	moduleDescription string
+	moduleVersion     string
+	moduleImpls       []string
+	// moduleKind is "client" for modules wrapping an external API (see generate module-client).
+	moduleKind        string
+	moduleClientURL   string
+	moduleRetryClient bool
+)
+
 func init() {
+	createModuleCmd.Flags().StringVar(&moduleTemplateDir, "template-dir", "", "module template override directory (default is .grob/templates/module in the project root)")
+	createModuleCmd.Flags().BoolVar(&moduleNoRegister, "no-register", false, "generate the module without registering it in the app's main file")
+	createModuleCmd.Flags().StringArrayVar(&moduleDeps, "dependency", nil, `inject a dependency into the service constructor, e.g. "*redis.Client=github.com/redis/go-redis/v9" (repeatable)`)
+	createModuleCmd.Flags().StringVar(&moduleRespFormat, "response-format", "", `JSON response style of generated handlers: "raw" or "envelope" (default from .grobrc, else raw)`)
+	createModuleCmd.Flags().StringVar(&moduleDirStyle, "dir-style", "", `where the module directory is created: "flat" (internal/<app>/<module>) or "modules"/"nested" (internal/<app>/modules/<module>) (default from .grobrc, else flat)`)
+	createModuleCmd.Flags().StringVar(&moduleTransport, "transport", "http", "how the module's service is exposed: http (gin controller), grpc (gRPC server), or both")
+	createModuleCmd.Flags().BoolVar(&moduleCtx, "ctx", false, "give service methods a context.Context first argument, passed the request context by the controller")
+	createModuleCmd.Flags().BoolVar(&moduleInterface, "interface-only", false, "generate only the service and repository ports (interfaces) in ports.go and a placeholder adapter file, without implementations")
+	createModuleCmd.Flags().BoolVar(&moduleSubpackages, "subpackages", false, "split the module into handler, service, and repository subpackages wired together by the module's Register")
+	createModuleCmd.Flags().StringVar(&moduleDescription, "description", "", `what the module does, used in the doc comments of its module, service, and controller, e.g. "handles login, logout, and token refresh" (also {{.ModuleDescription}} in custom templates)`)
+	createModuleCmd.Flags().StringVar(&moduleVersion, "version", "", "mount the module's controller under an API version prefix, e.g. v2 for /v2/<module> (see 'grob generate api-version')")
+	createModuleCmd.Flags().StringSliceVar(&moduleImpls, "impls", nil, "make the service an interface with one implementation per build tag, e.g. real,fake; the first is built unless another's tag is set")
+	createModuleCmd.Flags().StringVar(&testFramework, "test-framework", "", `generate a controller test with "stdlib" or "testify" assertions (default from .grobrc, else no test)`)
+	createModuleCmd.Flags().StringArrayVar(&moduleVars, "var", nil, `extra data for custom module templates, e.g. "author=Jane" used as {{.author}} (repeatable)`)
 	rootCmd.AddCommand(createModuleCmd)
 }
 
@@ -24,31 +62,568 @@ var createModuleCmd = &cobra.Command{
 		moduleName := args[1]
 		log.Printf("Creating new module '%s' in app '%s'", moduleName, appName)
 
-		projectRoot, err := utils.FindProjectRoot()
+		projectRoot, err := findProjectRoot()
 		if err != nil {
 			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
 		}
-		projectName := utils.GetProjectName(projectRoot)
 
-		moduleDir := filepath.Join(projectRoot, "internal", appName, moduleName)
-		if err := os.Mkdir(moduleDir, 0755); err != nil {
-			log.Fatalf("Failed to create module directory: %v", err)
+		files, err := createModule(projectRoot, appName, moduleName)
+		if err != nil {
+			log.Fatal(err)
 		}
+		reportCreated(projectRoot, files)
+	},
+}
+
+// createModule generates a module inside an app and registers it in the app's main file.
+// It returns the files it wrote, including those written before an error.
+func createModule(projectRoot, appName, moduleName string) ([]string, error) {
+	var files createdFiles
+	pkgName, typeName := utils.ModuleNames(moduleName)
+	if !token.IsIdentifier(pkgName) || !token.IsIdentifier(typeName) {
+		return files, fmt.Errorf("module name %q does not make a valid Go package name", moduleName)
+	}
+	if pkgName != moduleName {
+		log.Printf("Using package name '%s' and type prefix '%s' for module '%s'.", pkgName, typeName, moduleName)
+	}
+	moduleName = pkgName
+	if _, err := os.Stat(appMainPath(projectRoot, appName)); err != nil {
+		return files, fmt.Errorf("app '%s' not found: %w", appName, err)
+	}
+
+	data := utils.TemplateData(projectRoot)
+	data["AppName"] = appName
+	data["ModuleName"] = moduleName
+	data["ModuleType"] = typeName
+	data["ServiceCtx"] = ""
+	if moduleCtx {
+		data["ServiceCtx"] = "true"
+	}
+	data["InterfaceOnly"] = ""
+	if moduleInterface {
+		data["InterfaceOnly"] = "true"
+	}
+	data["ModuleDescription"] = describeModule(moduleDescription)
+	impls, err := parseImpls(moduleImpls)
+	if err != nil {
+		return files, err
+	}
+	data["ServiceImpls"] = ""
+	if len(impls) > 0 {
+		data["ServiceImpls"] = describeImpls(impls)
+		data["ServiceImplTag"] = impls[1]
+	}
+	projectName := data["ProjectName"]
 
-		data := map[string]string{
-			"ProjectName": projectName,
-			"AppName":     appName,
-			"ModuleName":  moduleName,
+	if moduleRespFormat != "" {
+		data["ResponseFormat"] = moduleRespFormat
+	}
+	switch data["ResponseFormat"] {
+	case "raw":
+	case "envelope":
+	default:
+		return files, fmt.Errorf("unknown response format %q: use raw or envelope", data["ResponseFormat"])
+	}
+
+	switch moduleTransport {
+	case "http", "grpc", "both":
+		data["Transport"] = moduleTransport
+	default:
+		return files, fmt.Errorf("unknown transport %q: use http, grpc, or both", moduleTransport)
+	}
+	// Apps created with --grpc-port serve the modules' gRPC servers themselves.
+	data["GRPCServerGroup"] = ""
+	if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "grpcserver")); err == nil && moduleTransport != "http" {
+		data["GRPCServerGroup"] = "true"
+	}
+
+	if moduleDirStyle != "" {
+		if err := utils.ValidateDirStyle(moduleDirStyle); err != nil {
+			return files, err
 		}
-		utils.CreateFileFromTmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName)), templates.ModuleTmpl, data)
-		utils.CreateFileFromTmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName)), templates.ServiceTmpl, data)
-		utils.CreateFileFromTmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName)), templates.ControllerTmpl, data)
+		data["DirStyle"] = moduleDirStyle
+	}
+	moduleDir := utils.ModuleDir(projectRoot, appName, moduleName, data["DirStyle"])
+	importPath := utils.ModuleImportPath(projectName, appName, moduleName, data["DirStyle"])
+	data["ModuleImportPath"] = importPath
 
-		appMainPath := filepath.Join(projectRoot, "internal", appName, fmt.Sprintf("%s_main.go", appName))
-		if err := utils.AddModuleToAppMain(appMainPath, projectName, appName, moduleName); err != nil {
-			log.Fatalf("Failed to auto-register module: %v", err)
+	deps, err := parseDependencies(moduleDeps)
+	if err != nil {
+		return files, err
+	}
+	if moduleInterface && (len(deps) > 0 || moduleTransport != "http") {
+		return files, fmt.Errorf("--interface-only generates no service implementation or transport; drop --dependency and --transport")
+	}
+	if moduleSubpackages && (moduleInterface || len(deps) > 0 || moduleTransport != "http") {
+		return files, fmt.Errorf("--subpackages cannot be combined with --interface-only, --dependency, or --transport")
+	}
+	if moduleVersion != "" {
+		if !apiVersionName.MatchString(moduleVersion) {
+			return files, fmt.Errorf("invalid --version %q: use a version such as v1 or v2", moduleVersion)
+		}
+		if moduleInterface || moduleSubpackages || moduleTransport == "grpc" {
+			return files, fmt.Errorf("--version mounts the module's controller, which --interface-only, --subpackages, and --transport grpc do not generate")
 		}
+	}
+	switch testFramework {
+	case "", utils.TestStdlib, utils.TestTestify:
+	default:
+		return files, fmt.Errorf("unknown test framework %q: use stdlib or testify", testFramework)
+	}
+	if len(impls) > 0 && (moduleInterface || moduleSubpackages) {
+		return files, fmt.Errorf("--impls cannot be combined with --interface-only or --subpackages")
+	}
+	addDependencyData(data, deps)
 
-		log.Printf("Module '%s' created and registered successfully in app '%s'.", moduleName, appName)
-	},
+	if err := addVars(data, moduleVars); err != nil {
+		return files, err
+	}
+
+	importName := utils.ModuleImportName(moduleName)
+	if importName != moduleName {
+		log.Printf("Warning: module name '%s' collides with an existing package; it will be imported as '%s'.", moduleName, importName)
+	}
+
+	templateDir := moduleTemplateDir
+	if templateDir == "" {
+		templateDir = filepath.Join(projectRoot, ".grob", "templates", "module")
+	}
+	manifest, err := utils.LoadManifest(templateDir)
+	if err != nil {
+		return files, fmt.Errorf("failed to load module template manifest: %w", err)
+	}
+
+	// Everything is validated; only now are files written.
+	if parent := filepath.Dir(moduleDir); parent != filepath.Join(projectRoot, "internal", appName) {
+		if err := os.MkdirAll(parent, utils.DirMode); err != nil {
+			return files, fmt.Errorf("failed to create module directory: %w", err)
+		}
+	}
+	if err := os.Mkdir(moduleDir, utils.DirMode); err != nil {
+		return files, fmt.Errorf("failed to create module directory: %w", err)
+	}
+	if data["ResponseFormat"] == "envelope" {
+		if err := ensureResponsePackage(projectRoot, data, &files); err != nil {
+			return files, err
+		}
+	}
+
+	if moduleKind == "client" {
+		data["ClientEnvPrefix"] = utils.EnvPrefix(appName) + "_" + utils.EnvPrefix(moduleName)
+		data["ClientBaseURL"] = moduleClientURL
+		data["RetryClient"] = ""
+		if moduleRetryClient {
+			data["RetryClient"] = "true"
+			if err := ensureRetryClient(projectRoot, data, &files); err != nil {
+				return files, err
+			}
+		}
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+		if err := files.tmpl(modulePath, templates.ModuleTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.client.go", moduleName)), templates.APIClientTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName)), templates.APIClientServiceTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName)), templates.APIClientControllerTmpl, data); err != nil {
+			return files, err
+		}
+		if _, err := utils.AddProviderToModule(modulePath, "New"+typeName+"Client"); err != nil {
+			return files, fmt.Errorf("failed to register the API client: %w", err)
+		}
+	} else if moduleInterface {
+		if manifest != nil {
+			log.Printf("Warning: the module template manifest in %s is not used with --interface-only.", templateDir)
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName)), templates.ModuleTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, "ports.go"), templates.ModulePortsTmpl, data); err != nil {
+			return files, err
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.adapter.go", moduleName)), templates.ModuleAdapterTmpl, data); err != nil {
+			return files, err
+		}
+		addNextStep("Implement %sService and %sRepository from %s/ports.go in adapters, and provide their constructors in %sModule.Register.", typeName, typeName, moduleName, typeName)
+	} else if moduleSubpackages {
+		if manifest != nil {
+			log.Printf("Warning: the module template manifest in %s is not used with --subpackages.", templateDir)
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName)), templates.SubpackageModuleTmpl, data); err != nil {
+			return files, err
+		}
+		for _, layer := range []struct{ name, tmpl string }{
+			{"repository", templates.SubpackageRepositoryTmpl},
+			{"service", templates.SubpackageServiceTmpl},
+			{"handler", templates.SubpackageHandlerTmpl},
+		} {
+			dir := filepath.Join(moduleDir, layer.name)
+			if err := os.Mkdir(dir, utils.DirMode); err != nil {
+				return files, fmt.Errorf("failed to create %s package: %w", layer.name, err)
+			}
+			if err := files.tmpl(filepath.Join(dir, layer.name+".go"), layer.tmpl, data); err != nil {
+				return files, err
+			}
+		}
+	} else if manifest != nil {
+		log.Printf("Using module template manifest from %s", templateDir)
+		if len(impls) > 0 {
+			log.Println("Warning: --impls only fills the ServiceImpls template data in manifest mode; the manifest decides which files are generated.")
+		}
+		if moduleTransport != "http" {
+			log.Println("Warning: --transport only selects the built-in templates; the manifest decides which files are generated.")
+		}
+		if len(deps) > 0 {
+			log.Println("Warning: --dependency only fills the ServiceFields/ServiceParams template data in manifest mode; register the providers in your templates.")
+		}
+		if err := createModuleFromManifest(moduleDir, templateDir, manifest, data, &files); err != nil {
+			return files, err
+		}
+	} else {
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName)), templates.ModuleTmpl, data); err != nil {
+			return files, err
+		}
+		if len(impls) > 0 {
+			if err := createServiceImpls(moduleDir, data, impls, &files); err != nil {
+				return files, err
+			}
+		} else if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName)), templates.ServiceTmpl, data); err != nil {
+			return files, err
+		}
+		if moduleTransport != "grpc" {
+			if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName)), templates.ControllerTmpl, data); err != nil {
+				return files, err
+			}
+			if err := createModuleTest(projectRoot, moduleDir, data, len(deps) > 0, &files); err != nil {
+				return files, err
+			}
+		}
+		if moduleTransport != "http" {
+			if err := createGRPCServer(projectRoot, moduleDir, data, &files); err != nil {
+				return files, err
+			}
+		}
+
+		if len(deps) > 0 {
+			if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.dependencies.go", moduleName)), templates.DependenciesTmpl, data); err != nil {
+				return files, err
+			}
+			modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+			for _, dep := range deps {
+				if _, err := utils.AddProviderToModule(modulePath, dep.Constructor()); err != nil {
+					return files, fmt.Errorf("failed to register dependency %s: %w", dep.Type, err)
+				}
+			}
+		}
+	}
+
+	if moduleVersion != "" {
+		if err := ensureAPIVersion(projectRoot, data, []string{moduleVersion}, &files); err != nil {
+			return files, err
+		}
+		if err := mountModuleVersion(moduleDir, data, moduleVersion, &files); err != nil {
+			return files, err
+		}
+		log.Printf("%sController mounted at /%s/%s.", typeName, moduleVersion, moduleName)
+	}
+
+	if moduleNoRegister {
+		log.Printf("Module '%s' created.", moduleName)
+		addNextStep("Register it in internal/%s/%s_main.go:\n  import %s \"%s\"\n  app := core.New(..., %s.%sModule{})", appName, appName, importName, importPath, importName, typeName)
+		return files, nil
+	}
+
+	appMainPath := filepath.Join(projectRoot, "internal", appName, fmt.Sprintf("%s_main.go", appName))
+	if err := utils.AddModuleToAppMain(appMainPath, importPath, moduleName, typeName); err != nil {
+		return files, fmt.Errorf("failed to auto-register module: %w", err)
+	}
+
+	log.Printf("Module '%s' created and registered successfully in app '%s'.", moduleName, appName)
+	return files, nil
+}
+
+// createGRPCServer adds a gRPC server for the module's service, described in
+// proto/<module>.proto, and requires the gRPC libraries it uses.
+func createGRPCServer(projectRoot, moduleDir string, data map[string]string, files *createdFiles) error {
+	moduleName := data["ModuleName"]
+	protoDir := filepath.Join(moduleDir, "proto")
+	if err := os.Mkdir(protoDir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create proto directory: %w", err)
+	}
+	if err := files.tmpl(filepath.Join(protoDir, moduleName+".proto"), templates.ModuleProtoTmpl, data); err != nil {
+		return err
+	}
+	if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.grpc.go", moduleName)), templates.GRPCServerTmpl, data); err != nil {
+		return err
+	}
+
+	for _, req := range [][2]string{
+		{"google.golang.org/grpc", "v1.58.3"},
+		{"google.golang.org/protobuf", "v1.34.2"},
+	} {
+		if err := utils.AddRequire(projectRoot, req[0], req[1]); err != nil {
+			return fmt.Errorf("failed to update go.mod: %w", err)
+		}
+	}
+	if data["GRPCServerGroup"] == "" {
+		addNextStep("Register %sGRPCServer on the app's gRPC server, e.g. by appending its Register method to gateway.Services (see 'grob generate grpc-gateway').", data["ModuleType"])
+	}
+	addNextStep("Run 'go mod tidy'.")
+	return nil
+}
+
+// createModuleTest adds a test of the module's controller when the project or
+// --test-framework chooses a test framework. Services with dependencies are
+// left untested, since the test cannot construct them.
+func createModuleTest(projectRoot, moduleDir string, data map[string]string, hasDeps bool, files *createdFiles) error {
+	if testFramework == "" && data["TestFramework"] == "" {
+		return nil
+	}
+	if err := useTestFramework(projectRoot, data, testFramework); err != nil {
+		return err
+	}
+	if hasDeps {
+		log.Printf("Warning: no test generated for %sController: the test cannot construct %sService's dependencies.", data["ModuleType"], data["ModuleType"])
+		return nil
+	}
+	if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.controller_test.go", data["ModuleName"])), templates.ModuleTestTmpl, data); err != nil {
+		return err
+	}
+	if data["TestFramework"] == utils.TestTestify {
+		addNextStep("Run 'go mod tidy' to fetch testify.")
+	}
+	return nil
+}
+
+// implName matches the --impls names, which are used as build tags.
+var implName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// parseImpls validates the --impls names, which must be distinct build tags.
+func parseImpls(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var impls []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if !implName.MatchString(v) {
+			return nil, fmt.Errorf("invalid implementation name %q: use a lower-case build tag such as real or fake", v)
+		}
+		if seen[v] {
+			return nil, fmt.Errorf("implementation %s is listed twice in --impls", v)
+		}
+		seen[v] = true
+		impls = append(impls, v)
+	}
+	if len(impls) < 2 {
+		return nil, fmt.Errorf("--impls needs at least two implementations, e.g. real,fake")
+	}
+	return impls, nil
+}
+
+// describeImpls lists the implementations for doc comments, e.g.
+// "real (default) and fake".
+func describeImpls(impls []string) string {
+	names := append([]string{impls[0] + " (default)"}, impls[1:]...)
+	last := len(names) - 1
+	if last == 1 {
+		return names[0] + " and " + names[1]
+	}
+	return strings.Join(names[:last], ", ") + ", and " + names[last]
+}
+
+// createServiceImpls makes the module's service an interface, in
+// <module>.service.go, with one build-tagged <module>.service.<impl>.go file
+// per implementation. Each file defines New<Module>Service, so the module
+// provides the service the same way whichever implementation is built: the
+// first unless one of the others' tags is set.
+func createServiceImpls(moduleDir string, data map[string]string, impls []string, files *createdFiles) error {
+	moduleName := data["ModuleName"]
+	if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName)), templates.ServiceImplsTmpl, data); err != nil {
+		return err
+	}
+	others := make([]string, len(impls)-1)
+	for i, impl := range impls[1:] {
+		others[i] = "!" + impl
+	}
+	for i, impl := range impls {
+		data["ImplName"] = impl
+		words := utils.SplitWords(impl)
+		data["ImplType"] = strings.ToLower(words[0]) + utils.GoName(strings.Join(words[1:], "_")) + data["ModuleType"] + "Service"
+		data["ImplBuildTag"] = impl
+		data["ImplDefault"] = ""
+		if i == 0 {
+			data["ImplBuildTag"] = strings.Join(others, " && ")
+			data["ImplDefault"] = "true"
+		}
+		if err := files.tmpl(filepath.Join(moduleDir, fmt.Sprintf("%s.service.%s.go", moduleName, impl)), templates.ServiceImplTmpl, data); err != nil {
+			return err
+		}
+	}
+	addNextStep("Build or test with another %sService implementation with '-tags <impl>', e.g. 'go test -tags %s ./...'; set only one of %s.", data["ModuleType"], impls[1], strings.Join(impls[1:], ", "))
+	return nil
+}
+
+// ensureResponsePackage creates the shared pkg/response envelope helpers unless they exist.
+func ensureResponsePackage(projectRoot string, data map[string]string, files *createdFiles) error {
+	dir := filepath.Join(projectRoot, "pkg", "response")
+	path := filepath.Join(dir, "response.go")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create response package: %w", err)
+	}
+	if err := files.tmpl(path, templates.ResponseTmpl, data); err != nil {
+		return err
+	}
+	log.Printf("Created shared response helpers in %s", dir)
+	return nil
+}
+
+// parseDependencies parses the --dependency flag values, rejecting duplicates.
+func parseDependencies(values []string) ([]utils.Dependency, error) {
+	var deps []utils.Dependency
+	seen := map[string]bool{}
+	for _, v := range values {
+		dep, err := utils.ParseDependency(v)
+		if err != nil {
+			return nil, err
+		}
+		if seen[dep.Name] {
+			return nil, fmt.Errorf("dependency %s is declared twice", dep.Type)
+		}
+		seen[dep.Name] = true
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// addVars merges --var key=value pairs into the template data. Built-in keys
+// cannot be overridden, so custom templates can rely on them.
+func addVars(data map[string]string, values []string) error {
+	vars := map[string]string{}
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --var %q: use key=value", v)
+		}
+		if _, builtin := data[key]; builtin {
+			return fmt.Errorf("--var %s would override built-in template data", key)
+		}
+		if _, dup := vars[key]; dup {
+			return fmt.Errorf("--var %s is set twice", key)
+		}
+		vars[key] = value
+	}
+	for k, v := range vars {
+		data[k] = v
+	}
+	return nil
+}
+
+// addDependencyData adds the template data that injects deps into the generated service.
+func addDependencyData(data map[string]string, deps []utils.Dependency) {
+	var imports, fields, params, assigns, constructors []string
+	for _, dep := range deps {
+		if dep.ImportPath != "" {
+			imports = append(imports, fmt.Sprintf("\t%q", dep.ImportPath))
+		}
+		fields = append(fields, fmt.Sprintf("\t%s %s", dep.Name, dep.Type))
+		params = append(params, fmt.Sprintf("%s %s", dep.Name, dep.Type))
+		assigns = append(assigns, fmt.Sprintf("%s: %s", dep.Name, dep.Name))
+		constructors = append(constructors, fmt.Sprintf(`
+// %s provides the %s dependency.
+// TODO: configure and return a real %s here.
+func %s() (%s, error) {
+	var zero %s
+	return zero, errors.New("%s is not configured yet; see %s")
+}
+`, dep.Constructor(), dep.Type, dep.Type, dep.Constructor(), dep.Type, dep.Type, dep.Type, dep.Constructor()))
+	}
+	data["ServiceImports"] = strings.Join(imports, "\n")
+	data["ServiceFields"] = strings.Join(fields, "\n")
+	data["ServiceParams"] = strings.Join(params, ", ")
+	data["ServiceAssigns"] = strings.Join(assigns, ", ")
+	data["DependencyConstructors"] = strings.Join(constructors, "")
+}
+
+// createModuleFromManifest renders every file declared in a module template manifest
+// and registers the declared providers in the module's Register method.
+func createModuleFromManifest(moduleDir, templateDir string, manifest *utils.Manifest, data map[string]string, files *createdFiles) error {
+	// Check every file name before writing any file.
+	paths := make([]string, len(manifest.Files))
+	for i, entry := range manifest.Files {
+		name, err := utils.RenderString(entry.Name, data)
+		if err != nil {
+			return fmt.Errorf("failed to render file name %q: %w", entry.Name, err)
+		}
+		if paths[i], err = utils.JoinWithin(moduleDir, name); err != nil {
+			return fmt.Errorf("file name %w", err)
+		}
+	}
+
+	var created, providers []string
+	for i, entry := range manifest.Files {
+		tmplPath, err := utils.JoinWithin(templateDir, entry.Template)
+		if err != nil {
+			return fmt.Errorf("template %w", err)
+		}
+		tmplBytes, err := os.ReadFile(tmplPath)
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", entry.Template, err)
+		}
+
+		path := paths[i]
+		if err := files.customTmpl(path, tmplPath, string(tmplBytes), data); err != nil {
+			return err
+		}
+		created = append(created, path)
+
+		if entry.Provide != "" {
+			provider, err := utils.RenderString(entry.Provide, data)
+			if err != nil {
+				return fmt.Errorf("failed to render provider %q: %w", entry.Provide, err)
+			}
+			providers = append(providers, provider)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil
+	}
+	for _, path := range created {
+		if filepath.Ext(path) != ".go" {
+			continue
+		}
+		registered := false
+		for _, provider := range providers {
+			ok, err := utils.AddProviderToModule(path, provider)
+			if err != nil {
+				return fmt.Errorf("failed to register provider %s in %s: %w", provider, path, err)
+			}
+			registered = ok
+		}
+		if registered {
+			return nil
+		}
+	}
+	return fmt.Errorf("manifest declares providers but no generated file has a Register method")
+}
+
+// describeModule normalizes a --description into a phrase that completes
+// "<Type>Service ...": whitespace is collapsed, a trailing period dropped, and
+// the first letter lowered unless it starts an acronym such as "API".
+func describeModule(desc string) string {
+	desc = strings.TrimRight(strings.Join(strings.Fields(desc), " "), ".")
+	r := []rune(desc)
+	if len(r) > 1 && unicode.IsUpper(r[0]) && !unicode.IsUpper(r[1]) {
+		r[0] = unicode.ToLower(r[0])
+	}
+	return string(r)
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+// createdFiles lists the files a generation step wrote, in order, for logging
+// and tests.
+type createdFiles []string
+
+// tmpl renders a built-in template to path and records the file.
+func (f *createdFiles) tmpl(path, tmplStr string, data map[string]string) error {
+	if err := utils.WriteFileFromTmpl(path, tmplStr, data); err != nil {
+		return err
+	}
+	*f = append(*f, path)
+	return nil
+}
+
+// customTmpl renders a user-provided template, read from tmplPath, to path and
+// records the file.
+func (f *createdFiles) customTmpl(path, tmplPath, tmplStr string, data map[string]string) error {
+	if err := utils.WriteFileFromCustomTmpl(path, tmplPath, tmplStr, data); err != nil {
+		return err
+	}
+	*f = append(*f, path)
+	return nil
+}
+
+// add records files written by other means.
+func (f *createdFiles) add(paths ...string) {
+	*f = append(*f, paths...)
+}
+
+// reportCreated logs each created file relative to root.
+func reportCreated(root string, files []string) {
+	for _, path := range files {
+		if rel, err := filepath.Rel(root, path); err == nil {
+			path = rel
+		}
+		log.Printf("  created %s", filepath.ToSlash(path))
+	}
+}
+
+// projectFiles returns every file this run created under root, relative to it
+// and slash-separated, for the --json output and the project history. Unlike
+// createdFiles, it includes files written by any generator.
+func projectFiles(root string) []string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, path := range utils.CreatedFiles() {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(absRoot, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	return files
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// relPaths returns paths relative to root, slash-separated.
+func relPaths(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	rel := make([]string, len(paths))
+	for i, path := range paths {
+		r, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel[i] = filepath.ToSlash(r)
+	}
+	return rel
+}
+
+func TestCreatedFiles(t *testing.T) {
+	projectRoot := filepath.Join(t.TempDir(), "shop")
+	t.Setenv("HOME", t.TempDir())
+	files, err := newProject("example.com/shop", projectRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := relPaths(t, projectRoot, files), []string{"go.mod", ".gitignore", "internal/main.go", "README.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("newProject created %v, want %v", got, want)
+	}
+
+	files, err = createApp(projectRoot, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := relPaths(t, projectRoot, files), []string{"internal/api/core/core.go", "internal/api/api_main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("createApp created %v, want %v", got, want)
+	}
+
+	files, err = createModule(projectRoot, "api", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"internal/api/users/users.module.go", "internal/api/users/users.service.go", "internal/api/users/users.controller.go"}
+	if got := relPaths(t, projectRoot, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("createModule created %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	doctorFix     bool
+	doctorYes     bool
+	doctorStrict  bool
+	doctorOffline bool
+)
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "register apps and modules that are missing from the main files")
+	doctorCmd.Flags().BoolVarP(&doctorYes, "yes", "y", false, "with --fix, also remove registrations whose directories are gone")
+	doctorCmd.Flags().BoolVar(&doctorStrict, "strict", false, "also run 'go build ./...' and 'go vet ./...' and report their errors")
+	doctorCmd.Flags().BoolVar(&doctorOffline, "offline", false, "with --strict, skip the build and vet, which may download modules")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// problem is an inconsistency between the project's directories and its main
+// files, or with --strict, a failed build or vet.
+type problem struct {
+	desc string
+	// fix repairs the problem, if it can be repaired; removal marks fixes that delete code.
+	fix     func() error
+	removal bool
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that every app and module on disk is registered, and optionally repair it",
+	Long: `Check that the apps under internal/ are registered in internal/main.go (or have a
+cmd/<app>/main.go in the binaries layout), that every module is registered in
+its app's core.New call, and that no registration points at a missing directory.
+
+Directories matching a pattern in .grobignore (gitignore syntax, relative to the
+project root, e.g. "internal/shared/" or "**/generated/") are not scanned.
+
+With --fix, missing registrations are added. Removing dangling registrations
+deletes code, so it also requires --yes.
+
+With --strict, 'go build ./...' and 'go vet ./...' also run in the project root
+and their errors are reported as problems, so the command checks both the
+structure and that the code compiles. --offline skips them.
+
+The command exits non-zero if any problem remains, so it can gate commits and CI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		problems, err := diagnose(projectRoot)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if doctorStrict {
+			if doctorOffline {
+				log.Println("Skipping the build and vet checks (--offline).")
+			} else {
+				problems = append(problems, checkCompiles(projectRoot)...)
+			}
+		}
+		if len(problems) == 0 {
+			log.Println("No problems found.")
+			return
+		}
+
+		unfixed, fixable := 0, false
+		for _, p := range problems {
+			switch {
+			case !doctorFix || p.fix == nil:
+				log.Printf("Problem: %s", p.desc)
+				unfixed++
+				fixable = fixable || p.fix != nil
+			case p.removal && !doctorYes:
+				log.Printf("Skipped: %s (removing it requires --yes)", p.desc)
+				unfixed++
+			default:
+				if err := p.fix(); err != nil {
+					log.Fatalf("Failed to fix %s: %v", p.desc, err)
+				}
+				log.Printf("Fixed: %s", p.desc)
+			}
+		}
+		if unfixed > 0 {
+			if !doctorFix && fixable {
+				addNextStep("Run 'grob doctor --fix' to repair them.")
+			}
+			printNextSteps(cmd, args)
+			os.Exit(1)
+		}
+	},
+}
+
+// diagnose compares the apps and modules on disk with their registrations.
+func diagnose(projectRoot string) ([]problem, error) {
+	cfg, err := utils.LoadConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	projectName := utils.GetProjectName(projectRoot)
+	ignore, err := utils.LoadIgnore(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := appsOnDisk(projectRoot, ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []problem
+	if cfg.Binaries() {
+		problems = append(problems, diagnoseBinaries(projectRoot, projectName, apps, ignore)...)
+	} else {
+		found, err := diagnoseInternalMain(projectRoot, projectName, apps, ignore)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, found...)
+	}
+
+	for _, app := range apps {
+		found, err := diagnoseModules(projectRoot, projectName, app, ignore)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, found...)
+	}
+	return problems, nil
+}
+
+// checkCompiles runs 'go build ./...' and 'go vet ./...' in the project root
+// and returns a problem, with the tool's output, for each that fails. Vet is
+// skipped when the build fails, as it would report the same errors.
+func checkCompiles(projectRoot string) []problem {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return []problem{{desc: "go is not on PATH, so the project could not be built"}}
+	}
+	var env []string
+	if cfg, err := utils.LoadConfig(projectRoot); err == nil && cfg.PrivateRepos != "" {
+		env = append(os.Environ(), "GOPRIVATE="+cfg.PrivateRepos)
+	}
+
+	for _, args := range [][]string{
+		{"build", "-o", os.DevNull, "./..."},
+		{"vet", "./..."},
+	} {
+		log.Printf("Running go %s ./...", args[0])
+		run := exec.Command(goBin, args...)
+		run.Dir = projectRoot
+		run.Env = env
+		out, err := run.CombinedOutput()
+		if err != nil {
+			output := strings.TrimRight(string(out), "\n")
+			if output == "" {
+				output = err.Error()
+			}
+			return []problem{{desc: fmt.Sprintf("go %s ./... failed:\n%s", args[0], output)}}
+		}
+	}
+	return nil
+}
+
+// appsOnDisk returns the directories under internal/ that contain an
+// <app>_main.go and are not excluded by .grobignore.
+func appsOnDisk(projectRoot string, ignore *utils.Ignore) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(projectRoot, "internal"))
+	if err != nil {
+		return nil, err
+	}
+	var apps []string
+	for _, e := range entries {
+		if !e.IsDir() || ignore.Ignored(path.Join("internal", e.Name()), true) {
+			continue
+		}
+		if _, err := os.Stat(appMainPath(projectRoot, e.Name())); err == nil {
+			apps = append(apps, e.Name())
+		}
+	}
+	return apps, nil
+}
+
+func diagnoseInternalMain(projectRoot, projectName string, apps []string, ignore *utils.Ignore) ([]problem, error) {
+	mainPath := filepath.Join(projectRoot, "internal", "main.go")
+	registered, err := utils.RegisteredApps(mainPath, projectName)
+	if err != nil {
+		return nil, err
+	}
+	isRegistered := map[string]bool{}
+	for _, app := range registered {
+		isRegistered[app] = true
+	}
+
+	var problems []problem
+	for _, app := range apps {
+		if isRegistered[app] {
+			continue
+		}
+		problems = append(problems, problem{
+			desc: fmt.Sprintf("app '%s' is not registered in internal/main.go", app),
+			fix:  func() error { return utils.AddAppToInternalMain(mainPath, projectName, app) },
+		})
+	}
+	for _, app := range registered {
+		if ignore.Ignored(path.Join("internal", app), true) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", app)); err == nil {
+			continue
+		}
+		problems = append(problems, problem{
+			desc:    fmt.Sprintf("internal/main.go registers app '%s', whose directory is missing", app),
+			fix:     func() error { return utils.RemoveAppFromInternalMain(mainPath, projectName, app) },
+			removal: true,
+		})
+	}
+	return problems, nil
+}
+
+func diagnoseBinaries(projectRoot, projectName string, apps []string, ignore *utils.Ignore) []problem {
+	var problems []problem
+	for _, app := range apps {
+		binPath := filepath.Join(projectRoot, "cmd", app, "main.go")
+		if _, err := os.Stat(binPath); err == nil {
+			continue
+		}
+		problems = append(problems, problem{
+			desc: fmt.Sprintf("app '%s' has no cmd/%s/main.go", app, app),
+			fix: func() error {
+				if err := os.MkdirAll(filepath.Dir(binPath), utils.DirMode); err != nil {
+					return err
+				}
+				utils.CreateFileFromTmpl(binPath, templates.BinaryMainTmpl, map[string]string{
+					"ProjectName": projectName,
+					"AppName":     app,
+				})
+				return nil
+			},
+		})
+	}
+
+	binaries, _ := filepath.Glob(filepath.Join(projectRoot, "cmd", "*", "main.go"))
+	for _, binPath := range binaries {
+		app := filepath.Base(filepath.Dir(binPath))
+		if ignore.Ignored(path.Join("internal", app), true) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", app)); err == nil {
+			continue
+		}
+		binDir := filepath.Dir(binPath)
+		problems = append(problems, problem{
+			desc:    fmt.Sprintf("cmd/%s builds app '%s', whose directory is missing", app, app),
+			fix:     func() error { return os.RemoveAll(binDir) },
+			removal: true,
+		})
+	}
+	return problems
+}
+
+// diagnoseModules compares the modules in an app's directory with the ones its
+// core.New call registers. Apps without a core.New call (workers, jobs) are skipped.
+func diagnoseModules(projectRoot, projectName, app string, ignore *utils.Ignore) ([]problem, error) {
+	mainPath := appMainPath(projectRoot, app)
+	if _, err := os.Stat(filepath.Join(projectRoot, "internal", app, "core")); err != nil {
+		return nil, nil
+	}
+	registered, err := utils.ParseAppModules(mainPath)
+	if err != nil {
+		return nil, err
+	}
+	isRegistered := map[string]bool{}
+	for _, m := range registered {
+		isRegistered[m.ImportPath] = true
+	}
+
+	onDisk, err := modulesOnDisk(projectRoot, app, ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []problem
+	for _, modulePath := range onDisk {
+		rel, err := filepath.Rel(projectRoot, filepath.Dir(modulePath))
+		if err != nil {
+			return nil, err
+		}
+		importPath := path.Join(projectName, filepath.ToSlash(rel))
+		if isRegistered[importPath] {
+			continue
+		}
+		pkgName, err := packageName(modulePath)
+		if err != nil {
+			return nil, err
+		}
+		typeName := utils.FindModuleType(modulePath)
+		problems = append(problems, problem{
+			desc: fmt.Sprintf("module %sModule in %s is not registered in app '%s'", typeName, rel, app),
+			fix:  func() error { return utils.AddModuleToAppMain(mainPath, importPath, pkgName, typeName) },
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, m := range registered {
+		rel, ok := strings.CutPrefix(m.ImportPath, projectName+"/")
+		if !ok || seen[m.ImportPath] || ignore.Ignored(rel, true) {
+			continue
+		}
+		seen[m.ImportPath] = true
+		if _, err := os.Stat(filepath.Join(projectRoot, filepath.FromSlash(rel))); err == nil {
+			continue
+		}
+		importPath := m.ImportPath
+		problems = append(problems, problem{
+			desc:    fmt.Sprintf("app '%s' registers %s from %s, whose directory is missing", app, m.Expr, rel),
+			fix:     func() error { return utils.RemoveModuleFromAppMain(mainPath, importPath) },
+			removal: true,
+		})
+	}
+	return problems, nil
+}
+
+// modulesOnDisk returns the module files (<dir>/<dir>.module.go declaring a
+// *Module type) under an app directory, in either directory style, skipping
+// directories excluded by .grobignore.
+func modulesOnDisk(projectRoot, app string, ignore *utils.Ignore) ([]string, error) {
+	appDir := filepath.Join(projectRoot, "internal", app)
+	var modules []string
+	for _, pattern := range []string{"*/*.module.go", "modules/*/*.module.go"} {
+		paths, err := filepath.Glob(filepath.Join(appDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			rel, err := filepath.Rel(projectRoot, filepath.Dir(p))
+			if err != nil {
+				return nil, err
+			}
+			if ignore.Ignored(rel, true) {
+				continue
+			}
+			if filepath.Base(p) == filepath.Base(filepath.Dir(p))+".module.go" && utils.FindModuleType(p) != "" {
+				modules = append(modules, p)
+			}
+		}
+	}
+	sort.Strings(modules)
+	return modules, nil
+}
+
+// packageName returns the package name declared in a Go file.
+func packageName(path string) (string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	return node.Name.Name, nil
+}
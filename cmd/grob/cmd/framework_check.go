@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+// frameworkAPIErrors are compiler messages that mean the framework exists but
+// does not have the shape the generated code expects.
+var frameworkAPIErrors = []string{
+	"undefined:",
+	"has no field or method",
+	"cannot use",
+	"not enough arguments",
+	"too many arguments",
+	"does not implement",
+}
+
+// checkFramework builds a throwaway probe against the project's grob-framework
+// version and explains failures that point at an API mismatch. It only warns:
+// the project has already been created when it runs.
+func checkFramework(projectDir string) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		log.Println("Warning: go is not on PATH; skipping the framework compatibility check.")
+		return
+	}
+
+	probeDir, err := os.MkdirTemp(projectDir, "grob-probe-")
+	if err != nil {
+		log.Printf("Warning: could not run the framework compatibility check: %v", err)
+		return
+	}
+	defer os.RemoveAll(probeDir)
+	utils.CreateFileFromTmpl(filepath.Join(probeDir, "main.go"), templates.FrameworkProbeTmpl, nil)
+
+	log.Println("Checking grob-framework compatibility...")
+	build := exec.Command(goBin, "build", "-mod=mod", "-o", os.DevNull, "./"+filepath.Base(probeDir))
+	build.Dir = projectDir
+	// The probe only needs the project's module, and -mod=mod is refused in
+	// the workspace of a project created with --workspace.
+	build.Env = append(os.Environ(), "GOWORK=off")
+	if cfg, err := utils.LoadConfig(projectDir); err == nil && cfg.PrivateRepos != "" {
+		build.Env = append(build.Env, "GOPRIVATE="+cfg.PrivateRepos)
+	}
+	out, err := build.CombinedOutput()
+	if err == nil {
+		return
+	}
+
+	output := string(out)
+	if isFrameworkAPIError(output) {
+		log.Printf("Warning: the selected grob-framework version does not match the API grob generates code for:\n%s", output)
+		log.Println("Pick a compatible release with --framework-version (or point --framework-replace at a matching checkout).")
+		return
+	}
+	log.Printf("Warning: could not verify grob-framework compatibility:\n%s", output)
+	log.Println("Re-run with --offline to skip this check.")
+}
+
+// isFrameworkAPIError reports whether build output contains a compile error in
+// the probe itself, as opposed to a download or module resolution failure.
+func isFrameworkAPIError(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "main.go:") {
+			continue
+		}
+		for _, msg := range frameworkAPIErrors {
+			if strings.Contains(line, msg) {
+				return true
+			}
+		}
+	}
+	return false
+}
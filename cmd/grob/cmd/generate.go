@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+}
+
+var generateCmd = &cobra.Command{
+	Use:     "generate",
+	Aliases: []string{"g"},
+	Short:   "Generate additional building blocks for an existing Grob project",
+}
+
+// loadApp locates the project and checks that the app exists.
+// It returns the project root and template data prefilled with the app name.
+func loadApp(appName string) (string, map[string]string) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName)); err != nil {
+		log.Fatalf("App '%s' not found: %v", appName, err)
+	}
+
+	data := utils.TemplateData(projectRoot)
+	data["AppName"] = appName
+	data["EnvPrefix"] = utils.EnvPrefix(appName)
+	return projectRoot, data
+}
+
+// loadModule is loadApp for commands that extend an existing module. It sets
+// ModuleName and ModuleType, taking the type prefix from the module file so it
+// matches however the module was named, and DirStyle to the layout the module
+// was found in, and returns the module directory.
+func loadModule(appName, moduleName string) (string, map[string]string, string) {
+	projectRoot, data := loadApp(appName)
+	pkgName, typeName := utils.ModuleNames(moduleName)
+	moduleDir, style, err := utils.FindModuleDir(projectRoot, appName, pkgName, data["DirStyle"])
+	if err != nil {
+		log.Fatalf("Module '%s' not found in app '%s': %v", moduleName, appName, err)
+	}
+	data["DirStyle"] = style
+	if t := utils.FindModuleType(filepath.Join(moduleDir, pkgName+".module.go")); t != "" {
+		typeName = t
+	}
+	data["ModuleName"] = pkgName
+	data["ModuleType"] = typeName
+	return projectRoot, data, moduleDir
+}
+
+// appMainPath returns the path of an app's main file.
+func appMainPath(projectRoot, appName string) string {
+	return filepath.Join(projectRoot, "internal", appName, fmt.Sprintf("%s_main.go", appName))
+}
+
+// createPackageDir creates the directory for a generated package, refusing to overwrite an existing one.
+func createPackageDir(dir string) {
+	if err := os.Mkdir(dir, utils.DirMode); err != nil {
+		log.Fatalf("Failed to create directory %s: %v", dir, err)
+	}
+}
+
+// managementRouter matches the declaration of the management router in the
+// main files of apps created with --health-port.
+var managementRouter = regexp.MustCompile(`(?m)^\s*management := gin\.New\(\)`)
+
+// opsRouter returns the router that health checks, metrics, and pprof are
+// mounted on in an app's main file: the management router of apps with a
+// separate health port, or else app.Router().
+func opsRouter(mainPath string) string {
+	src, err := os.ReadFile(mainPath)
+	if err == nil && managementRouter.Match(src) {
+		return "management"
+	}
+	return "app.Router()"
+}
+
+// testFramework is the --test-framework flag of the commands that generate tests.
+var testFramework string
+
+// useTestFramework sets the TestFramework of generated tests from a
+// --test-framework flag, or the project's test_framework setting when the flag
+// is empty, and requires testify in go.mod when the tests use it.
+func useTestFramework(projectRoot string, data map[string]string, flag string) error {
+	if flag != "" {
+		data["TestFramework"] = flag
+	}
+	switch data["TestFramework"] {
+	case "", utils.TestStdlib:
+		return nil
+	case utils.TestTestify:
+		return utils.AddRequire(projectRoot, "github.com/stretchr/testify", "v1.10.0")
+	}
+	return fmt.Errorf("unknown test framework %q: use stdlib or testify", data["TestFramework"])
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var adminModel string
+
+func init() {
+	generateAdminCmd.Flags().StringVar(&adminModel, "model", "", "model to manage (default: the only model in the module)")
+	generateAdminCmd.Flags().StringVar(&repoID, "id", "ID", "model field holding the primary key")
+	generateCmd.AddCommand(generateAdminCmd)
+}
+
+var generateAdminCmd = &cobra.Command{
+	Use:   "admin-crud [app-name] [module-name]",
+	Short: "Generate server-rendered HTML admin pages for a module's model",
+	Long: `Generate HTML list, detail, and form pages plus a controller to manage a model
+under /admin/<module>. The pages read and write through the model's repository,
+so run 'grob generate sql-repository' for the model first.
+
+Apps with 'grob generate admin-auth' serve the pages behind its authentication.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating admin pages for module '%s' in app '%s'", moduleName, appName)
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		model, err := findModel(moduleDir, adminModel)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		dir := strings.ToLower(model.Name)
+		repoPath := filepath.Join(moduleDir, dir+".repository.go")
+		if _, err := os.Stat(repoPath); err != nil {
+			log.Fatalf("%sRepository not found; run 'grob generate sql-repository %s %s' first", model.Name, appName, moduleName)
+		}
+
+		data["ModelName"] = model.Name
+		data["AdminDir"] = dir
+		data["AdminPath"] = "/admin/" + data["ModuleName"]
+		if err := addAdminFields(data, model, repoID); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		controllerPath := filepath.Join(moduleDir, dir+".admin.go")
+		pagesDir := filepath.Join(moduleDir, "admin", dir)
+		for _, path := range []string{controllerPath, pagesDir} {
+			if _, err := os.Stat(path); err == nil {
+				log.Fatalf("%s already exists", path)
+			}
+		}
+		if err := os.MkdirAll(pagesDir, utils.DirMode); err != nil {
+			log.Fatalf("Failed to create %s: %v", pagesDir, err)
+		}
+		utils.CreateFileFromTmpl(controllerPath, templates.AdminControllerTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(pagesDir, "list.tmpl"), templates.AdminListPageTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(pagesDir, "form.tmpl"), templates.AdminFormPageTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(pagesDir, "detail.tmpl"), templates.AdminDetailPageTmpl, data)
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", data["ModuleName"]))
+		ctor := "New" + model.Name + "AdminController"
+		ok, err := utils.AddProviderToModule(modulePath, ctor)
+		if err != nil {
+			log.Fatalf("Failed to register %s: %v", ctor, err)
+		}
+		if !ok {
+			addProvideStep(ctor, modulePath)
+		}
+
+		log.Printf("Admin pages created in %s.", pagesDir)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "adminauth")); err != nil {
+			addNextStep("Protect and mount the pages with 'grob generate admin-auth %s', or register %sAdminController's routes on a group at %s.", appName, model.Name, data["AdminPath"])
+			return
+		}
+		var files createdFiles
+		ok, err = mountAdminRoute(moduleDir, data, model.Name, data["AdminPath"], &files)
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if ok {
+			log.Printf("%sAdminController mounted at %s behind the admin authentication.", model.Name, data["AdminPath"])
+		}
+	},
+}
+
+// addAdminFields renders the per-field parts of the admin pages: table columns,
+// detail rows, form inputs, and the code binding the form back to the model.
+// Fields of types the form cannot edit are shown but left out of the form.
+func addAdminFields(data map[string]string, model utils.Model, idField string) error {
+	var id *utils.ModelField
+	for i, f := range model.Fields {
+		if f.Name == idField {
+			id = &model.Fields[i]
+		}
+	}
+	if id == nil {
+		return fmt.Errorf("model %s has no %s field; choose the key with --id", model.Name, idField)
+	}
+
+	imports := map[string]bool{}
+	switch {
+	case id.Type == "string":
+		data["AdminParseID"] = "\tid := ctx.Param(\"id\")"
+	case integerTypes[id.Type]:
+		imports["strconv"] = true
+		parse := "strconv.ParseInt(ctx.Param(\"id\"), 10, 64)"
+		if strings.HasPrefix(id.Type, "uint") {
+			parse = "strconv.ParseUint(ctx.Param(\"id\"), 10, 64)"
+		}
+		data["AdminParseID"] = fmt.Sprintf("\tn, err := %s\n\tif err != nil {\n\t\tctx.String(http.StatusNotFound, \"invalid id\")\n\t\treturn nil, false\n\t}\n\tid := %s(n)", parse, id.Type)
+	default:
+		return fmt.Errorf("unsupported %s type %s: use a string or integer key", id.Name, id.Type)
+	}
+
+	var headers, cells, rows, inputs, binds []string
+	for _, f := range model.Fields {
+		label := strings.Join(utils.SplitWords(f.Name), " ")
+		value := "." + f.Name
+		if f.Type == "time.Time" {
+			value += `.Format "2006-01-02 15:04"`
+		}
+		headers = append(headers, fmt.Sprintf("<th>%s</th>", label))
+		cells = append(cells, fmt.Sprintf("<td>{{%s}}</td>", value))
+		rows = append(rows, fmt.Sprintf("<dt>%s</dt><dd>{{.Item%s}}</dd>", label, value))
+
+		if f.Name == id.Name && integerTypes[id.Type] {
+			continue
+		}
+		input, bind, pkg := adminFormField(f, label)
+		if input == "" {
+			continue
+		}
+		if pkg != "" {
+			imports[pkg] = true
+		}
+		inputs = append(inputs, input)
+		binds = append(binds, bind)
+	}
+
+	var importLines []string
+	for _, pkg := range []string{"strconv", "time"} {
+		if imports[pkg] {
+			importLines = append(importLines, fmt.Sprintf("\t%q", pkg))
+		}
+	}
+	data["AdminImports"] = strings.Join(importLines, "\n")
+	data["AdminIDField"] = id.Name
+	data["AdminListHeaders"] = strings.Join(headers, "\n")
+	data["AdminListCells"] = strings.Join(cells, "\n")
+	data["AdminColumnCount"] = fmt.Sprint(len(model.Fields) + 1)
+	data["AdminDetailRows"] = strings.Join(rows, "\n")
+	data["AdminFormInputs"] = strings.Join(inputs, "\n")
+	data["AdminBindFields"] = strings.Join(binds, "\n")
+	return nil
+}
+
+// adminFormField returns the form input for a field, the Go code that reads
+// it back into item, and the package that code needs. The input is empty for
+// types the form does not edit.
+func adminFormField(f utils.ModelField, label string) (input, bind, pkg string) {
+	name := f.Column
+	v := "v" + f.Name
+	invalid := fmt.Sprintf("\tif err != nil {\n\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t}\n", name)
+	field := func(typ, value string) string {
+		return fmt.Sprintf(`<p><label>%s <input type="%s" name="%s" value="{{%s}}"></label></p>`, label, typ, name, value)
+	}
+
+	switch f.Type {
+	case "string":
+		return field("text", ".Item."+f.Name),
+			fmt.Sprintf("\titem.%s = ctx.PostForm(%q)", f.Name, name), ""
+	case "bool":
+		return fmt.Sprintf(`<p><label><input type="checkbox" name="%s"{{if .Item.%s}} checked{{end}}> %s</label></p>`, name, f.Name, label),
+			fmt.Sprintf("\titem.%s = ctx.PostForm(%q) != \"\"", f.Name, name), ""
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		parse := fmt.Sprintf("strconv.ParseInt(ctx.PostForm(%q), 10, 64)", name)
+		if strings.HasPrefix(f.Type, "uint") {
+			parse = fmt.Sprintf("strconv.ParseUint(ctx.PostForm(%q), 10, 64)", name)
+		}
+		return field("number", ".Item."+f.Name),
+			fmt.Sprintf("\t%s, err := %s\n%s\titem.%s = %s(%s)", v, parse, invalid, f.Name, f.Type, v), "strconv"
+	case "float32", "float64":
+		return strings.Replace(field("number", ".Item."+f.Name), `type="number"`, `type="number" step="any"`, 1),
+			fmt.Sprintf("\t%s, err := strconv.ParseFloat(ctx.PostForm(%q), 64)\n%s\titem.%s = %s(%s)", v, name, invalid, f.Name, f.Type, v), "strconv"
+	case "time.Time":
+		return field("datetime-local", `.Item.`+f.Name+`.Format "2006-01-02T15:04"`),
+			fmt.Sprintf("\t%s, err := time.Parse(\"2006-01-02T15:04\", ctx.PostForm(%q))\n%s\titem.%s = %s", v, name, invalid, f.Name, v), "time"
+	}
+	return "", "", ""
+}
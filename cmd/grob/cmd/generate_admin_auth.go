@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	adminAuthMode       string
+	adminAuthSessionTTL time.Duration
+)
+
+// adminPathConst matches the <Model>AdminPath constant of the controllers
+// from 'grob generate admin-crud'.
+var adminPathConst = regexp.MustCompile(`(?m)^const (\w+)AdminPath = "([^"]*)"`)
+
+func init() {
+	generateAdminAuthCmd.Flags().StringVar(&adminAuthMode, "mode", "basic", "how admins authenticate: basic (HTTP basic auth) or session (login page and signed cookie)")
+	generateAdminAuthCmd.Flags().DurationVar(&adminAuthSessionTTL, "session-ttl", 12*time.Hour, "with --mode session, how long a login lasts")
+	generateCmd.AddCommand(generateAdminAuthCmd)
+}
+
+var generateAdminAuthCmd = &cobra.Command{
+	Use:   "admin-auth [app-name]",
+	Short: "Generate authentication for an app's /admin routes",
+	Long: `Generate internal/<app>/adminauth, serving the app's /admin routes behind
+authentication with the credentials in <APP>_ADMIN_USER and <APP>_ADMIN_PASSWORD.
+The app refuses to start without them, so the admin pages are never served
+unprotected. Credentials are compared in constant time.
+
+  --mode basic    HTTP basic authentication
+  --mode session  a login page at /admin/login and a signed session cookie,
+                  keyed with <APP>_ADMIN_SESSION_SECRET; the cookie is
+                  HttpOnly, SameSite=Strict, and Secure unless
+                  <APP>_ADMIN_INSECURE_COOKIE=true
+
+In the app's main.go, AdminAuthModule is registered and
+
+  adminauth.Mount(app.Router())
+
+creates the protected /admin group and registers on it the admin controllers
+that modules provide. The pages from 'grob generate admin-crud' are mounted
+there: those that exist now through a <model>.admin_route.go file, and later
+ones as they are generated.`,
+	Example: `  grob generate admin-auth api
+  grob generate admin-auth api --mode session --session-ttl 8h`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating admin authentication for app '%s'", appName)
+
+		switch adminAuthMode {
+		case "basic", "session":
+		default:
+			log.Fatalf("Unknown mode %q: use basic or session", adminAuthMode)
+		}
+		if adminAuthSessionTTL < time.Minute {
+			log.Fatal("--session-ttl must be at least a minute")
+		}
+
+		projectRoot, data := loadApp(appName)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Fatalf("App '%s' has no dependency injection container for modules to mount their admin pages with", appName)
+		}
+		dir := filepath.Join(projectRoot, "internal", appName, "adminauth")
+		if _, err := os.Stat(dir); err == nil {
+			log.Fatalf("%s already exists", dir)
+		}
+		data["AdminAuthMode"] = adminAuthMode
+		data["AdminSessionTTL"] = durationExpr(adminAuthSessionTTL)
+
+		var files createdFiles
+		err := os.Mkdir(dir, utils.DirMode)
+		if err == nil {
+			err = files.tmpl(filepath.Join(dir, "adminauth.go"), templates.AdminAuthTmpl, data)
+		}
+		if err == nil && adminAuthMode == "session" {
+			err = files.tmpl(filepath.Join(dir, "login.tmpl"), templates.AdminLoginPageTmpl, data)
+		}
+		var mounted []string
+		if err == nil {
+			mounted, err = mountAdminControllers(projectRoot, data, &files)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/internal/" + appName + "/adminauth"
+		if err := utils.AddModuleToAppMain(mainPath, importPath, "adminauth", "AdminAuth"); err != nil {
+			log.Fatalf("Failed to register AdminAuthModule: %v", err)
+		}
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "adminauth.Mount(app.Router())"); err != nil {
+			log.Fatalf("Failed to mount the admin routes in %s: %v", mainPath, err)
+		}
+
+		log.Printf("AdminAuthModule created and registered in app '%s'.", appName)
+		if len(mounted) > 0 {
+			log.Printf("Admin pages mounted behind it: %s.", strings.Join(mounted, ", "))
+		}
+		addNextStep("Keep adminauth.Mount after the app's middleware in %s: gin does not apply middleware to routes registered before it.", mainPath)
+		if adminAuthMode == "session" {
+			addNextStep("Set %[1]s_ADMIN_USER, %[1]s_ADMIN_PASSWORD, and %[1]s_ADMIN_SESSION_SECRET (at least 32 random characters); set %[1]s_ADMIN_INSECURE_COOKIE=true only to log in over plain HTTP in development.", data["EnvPrefix"])
+		} else {
+			addNextStep("Set %[1]s_ADMIN_USER and %[1]s_ADMIN_PASSWORD, and serve the app over HTTPS: basic auth sends the password with every request.", data["EnvPrefix"])
+		}
+	},
+}
+
+// mountAdminControllers mounts the app's existing admin-crud controllers
+// behind adminauth, returning the paths they are mounted at.
+func mountAdminControllers(projectRoot string, data map[string]string, files *createdFiles) ([]string, error) {
+	ignore, err := utils.LoadIgnore(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := modulesOnDisk(projectRoot, data["AppName"], ignore)
+	if err != nil {
+		return nil, err
+	}
+	var mounted []string
+	for _, modulePath := range modules {
+		moduleDir := filepath.Dir(modulePath)
+		controllers, err := filepath.Glob(filepath.Join(moduleDir, "*.admin.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range controllers {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			m := adminPathConst.FindSubmatch(src)
+			if m == nil {
+				continue
+			}
+			pkg, err := packageName(path)
+			if err != nil {
+				return nil, err
+			}
+			moduleData := maps.Clone(data)
+			moduleData["ModuleName"] = pkg
+			ok, err := mountAdminRoute(moduleDir, moduleData, string(m[1]), string(m[2]), files)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				mounted = append(mounted, string(m[2]))
+			}
+		}
+	}
+	return mounted, nil
+}
+
+// mountAdminRoute mounts a model's admin controller at adminPath behind
+// adminauth, through <model>.admin_route.go. It reports false, with a next
+// step, if adminPath is outside /admin or the route file already exists.
+func mountAdminRoute(moduleDir string, data map[string]string, modelName, adminPath string, files *createdFiles) (bool, error) {
+	routePath := strings.TrimPrefix(adminPath, "/admin")
+	if routePath == adminPath || !strings.HasPrefix(routePath, "/") {
+		addNextStep("Mount %sAdminController by hand: its path %s is outside /admin.", modelName, adminPath)
+		return false, nil
+	}
+	path := filepath.Join(moduleDir, strings.ToLower(modelName)+".admin_route.go")
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+	data["ModelName"] = modelName
+	data["AdminRoutePath"] = routePath
+	if err := files.tmpl(path, templates.AdminRouteTmpl, data); err != nil {
+		return false, err
+	}
+
+	modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", filepath.Base(moduleDir)))
+	ctor := "New" + modelName + "AdminRoute"
+	ok, err := utils.AddProviderToModule(modulePath, ctor)
+	if err != nil {
+		return false, fmt.Errorf("failed to register %s: %w", ctor, err)
+	}
+	if !ok {
+		addProvideStep(ctor, modulePath)
+	}
+	return true, nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	apiDocsFormat string
+	apiDocsOutput string
+)
+
+func init() {
+	generateAPIDocsCmd.Flags().StringVar(&apiDocsFormat, "format", "markdown", "output format; only markdown is supported")
+	generateAPIDocsCmd.Flags().StringVarP(&apiDocsOutput, "output", "o", filepath.Join("docs", "api.md"), "file to write, relative to the project root")
+	generateCmd.AddCommand(generateAPIDocsCmd)
+}
+
+var generateAPIDocsCmd = &cobra.Command{
+	Use:   "api-docs",
+	Short: "Generate a markdown index of every module's HTTP routes",
+	Long: `Generate docs/api.md, listing the routes each module registers in its
+RegisterRoutes functions, grouped by app and module, with their method, path,
+and handler. Paths are relative to the router group the module is mounted on.
+
+The file is rewritten from scratch each time, so run the command again after
+changing routes rather than editing it.`,
+	Example: `  grob generate api-docs
+  grob generate api-docs --output docs/routes.md`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if apiDocsFormat != "markdown" {
+			log.Fatalf("Unknown format %q: only markdown is supported", apiDocsFormat)
+		}
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+		ignore, err := utils.LoadIgnore(projectRoot)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		apps, err := appsOnDisk(projectRoot, ignore)
+		if err != nil {
+			log.Fatalf("Failed to list apps: %v", err)
+		}
+
+		var b strings.Builder
+		count := 0
+		for _, app := range apps {
+			fmt.Fprintf(&b, "\n## %s\n", app)
+			modules, err := modulesOnDisk(projectRoot, app, ignore)
+			if err != nil {
+				log.Fatalf("Failed to list the modules of app '%s': %v", app, err)
+			}
+			found := false
+			for _, modulePath := range modules {
+				routes, err := moduleRoutes(filepath.Dir(modulePath))
+				if err != nil {
+					log.Fatalf("Failed to read routes: %v", err)
+				}
+				if len(routes) == 0 {
+					continue
+				}
+				found = true
+				count += len(routes)
+				fmt.Fprintf(&b, "\n### %s\n\n| Method | Path | Handler |\n| --- | --- | --- |\n", filepath.Base(filepath.Dir(modulePath)))
+				for _, r := range routes {
+					fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", r.Method, r.Path, r.Handler)
+				}
+			}
+			if !found {
+				b.WriteString("\nNo module routes.\n")
+			}
+		}
+
+		data := utils.TemplateData(projectRoot)
+		data["APIDocsRoutes"] = b.String()
+		path := apiDocsOutput
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), utils.DirMode); err != nil {
+			log.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		var files createdFiles
+		err = files.tmpl(path, templates.APIDocsTmpl, data)
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Documented %d routes of %d apps in %s.", count, len(apps), path)
+	},
+}
+
+// moduleRoutes returns the routes registered by the non-test Go files of a
+// module directory, in file name order.
+func moduleRoutes(dir string) ([]utils.Route, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var routes []utils.Route
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		found, err := utils.ParseRoutes(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		routes = append(routes, found...)
+	}
+	return routes, nil
+}
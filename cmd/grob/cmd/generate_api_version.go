@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	apiVersions      []string
+	apiModuleVersion string
+)
+
+// apiVersionName matches the API versions used as route prefixes, e.g. v1 or v2beta.
+var apiVersionName = regexp.MustCompile(`^v[0-9]+[a-z0-9]*$`)
+
+func init() {
+	generateAPIVersionCmd.Flags().StringSliceVar(&apiVersions, "versions", []string{"v1"}, "API versions to create route groups for")
+	generateAPIVersionCmd.Flags().StringVar(&apiModuleVersion, "version", "", "with a module name, the version to mount the module's controller under (default: the first of --versions)")
+	generateCmd.AddCommand(generateAPIVersionCmd)
+}
+
+var generateAPIVersionCmd = &cobra.Command{
+	Use:   "api-version [app-name] [module-name]",
+	Short: "Generate versioned route groups, such as /v1 and /v2, for an app's controllers",
+	Long: `Generate internal/<app>/apiversion and route an app's API under version prefixes.
+
+In the app's main.go, APIVersionModule is registered and
+
+  apiversion.Mount(app.Router(), "v1", "v2")
+
+creates a router group per version and registers on it the controllers that
+modules mount under that version. Running the command again with more
+--versions adds them to the Mount call.
+
+With a module name, the module's controller is mounted at /<version>/<module>,
+through <module>.version.go. New modules are mounted with
+'grob create-module <app> <module> --version v2'.`,
+	Example: `  grob generate api-version api --versions v1,v2
+  grob generate api-version api users --version v1`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating API versioning for app '%s'", appName)
+
+		versions, err := parseAPIVersions(apiVersions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		version := apiModuleVersion
+		if version == "" {
+			version = versions[0]
+		} else if len(args) < 2 {
+			log.Fatal("--version needs a module name")
+		}
+		if !apiVersionName.MatchString(version) {
+			log.Fatalf("Invalid --version %q: use a version such as v1 or v2", version)
+		}
+
+		projectRoot, data := loadApp(appName)
+		var moduleDir string
+		if len(args) == 2 {
+			_, data, moduleDir = loadModule(appName, args[1])
+			versions = appendMissing(versions, version)
+		}
+
+		var files createdFiles
+		err = ensureAPIVersion(projectRoot, data, versions, &files)
+		if err == nil && moduleDir != "" {
+			err = mountModuleVersion(moduleDir, data, version, &files)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if moduleDir != "" {
+			log.Printf("%sController mounted at /%s/%s.", data["ModuleType"], version, data["ModuleName"])
+			return
+		}
+		log.Printf("Route groups for %s created in app '%s'.", strings.Join(versions, ", "), appName)
+		addNextStep("Mount modules under a version with 'grob generate api-version %s <module> --version <version>', or 'grob create-module %s <module> --version <version>' for new ones.", appName, appName)
+	},
+}
+
+// parseAPIVersions validates the --versions values, dropping duplicates.
+func parseAPIVersions(values []string) ([]string, error) {
+	var versions []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if !apiVersionName.MatchString(v) {
+			return nil, fmt.Errorf("invalid API version %q: use a version such as v1 or v2", v)
+		}
+		versions = appendMissing(versions, v)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("--versions needs at least one version")
+	}
+	return versions, nil
+}
+
+func appendMissing(list []string, s string) []string {
+	for _, e := range list {
+		if e == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// ensureAPIVersion creates the app's apiversion package unless it exists,
+// registers APIVersionModule in the app's main file, and makes its Mount call
+// create a group for each of the versions.
+func ensureAPIVersion(projectRoot string, data map[string]string, versions []string, files *createdFiles) error {
+	appName := data["AppName"]
+	if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+		return fmt.Errorf("app '%s' has no dependency injection container for modules to mount their routes with", appName)
+	}
+	dir := filepath.Join(projectRoot, "internal", appName, "apiversion")
+	if _, err := os.Stat(dir); err != nil {
+		if err := os.Mkdir(dir, utils.DirMode); err != nil {
+			return fmt.Errorf("failed to create apiversion package: %w", err)
+		}
+		if err := files.tmpl(filepath.Join(dir, "apiversion.go"), templates.APIVersionTmpl, data); err != nil {
+			return err
+		}
+	}
+
+	mainPath := appMainPath(projectRoot, appName)
+	importPath := data["ProjectName"] + "/internal/" + appName + "/apiversion"
+	modules, err := utils.ParseAppModules(mainPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", mainPath, err)
+	}
+	registered := false
+	for _, m := range modules {
+		registered = registered || m.ImportPath == importPath
+	}
+	if !registered {
+		if err := utils.AddModuleToAppMain(mainPath, importPath, "apiversion", "APIVersion"); err != nil {
+			return fmt.Errorf("failed to register APIVersionModule: %w", err)
+		}
+	}
+
+	for _, v := range versions {
+		found, err := utils.AddCallArgument(mainPath, "apiversion", "Mount", strconv.Quote(v))
+		if err != nil {
+			return fmt.Errorf("failed to add %s to apiversion.Mount in %s: %w", v, mainPath, err)
+		}
+		if found {
+			continue
+		}
+		quoted := make([]string, len(versions))
+		for i, v := range versions {
+			quoted[i] = strconv.Quote(v)
+		}
+		stmt := fmt.Sprintf("apiversion.Mount(app.Router(), %s)", strings.Join(quoted, ", "))
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, stmt); err != nil {
+			return fmt.Errorf("failed to mount the version groups in %s: %w", mainPath, err)
+		}
+		addNextStep("Keep apiversion.Mount after the app's middleware in %s: gin does not apply middleware to routes registered before it.", mainPath)
+		break
+	}
+	return nil
+}
+
+// mountModuleVersion mounts a module's controller under version, at the
+// module's name.
+func mountModuleVersion(moduleDir string, data map[string]string, version string, files *createdFiles) error {
+	moduleName, typeName := data["ModuleName"], data["ModuleType"]
+	if _, err := os.Stat(filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName))); err != nil {
+		return fmt.Errorf("module '%s' has no %sController to mount under %s", moduleName, typeName, version)
+	}
+	path := filepath.Join(moduleDir, fmt.Sprintf("%s.version.go", moduleName))
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists; edit its Version to move the module to another version", path)
+	}
+	data["APIVersion"] = version
+	data["APIVersionPath"] = "/" + moduleName
+	if err := files.tmpl(path, templates.ModuleVersionTmpl, data); err != nil {
+		return err
+	}
+
+	modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+	ctor := "New" + typeName + "VersionedRoutes"
+	ok, err := utils.AddProviderToModule(modulePath, ctor)
+	if err != nil {
+		return fmt.Errorf("failed to register %s: %w", ctor, err)
+	}
+	if !ok {
+		addProvideStep(ctor, modulePath)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var appTestEndpoints []string
+
+// appHealthRoute is the route of 'grob generate healthcheck' that app tests check
+// by default when it is registered on the app's public router.
+const appHealthRoute = "/healthz"
+
+func init() {
+	generateAppTestCmd.Flags().StringSliceVar(&appTestEndpoints, "endpoint", nil, "endpoint paths the test expects to return 200 (repeatable; default "+appHealthRoute+" when the app serves health checks)")
+	generateAppTestCmd.Flags().StringVar(&testFramework, "test-framework", "", `assertions of the generated tests: "stdlib" or "testify" (default from .grobrc, else stdlib)`)
+	generateCmd.AddCommand(generateAppTestCmd)
+}
+
+var generateAppTestCmd = &cobra.Command{
+	Use:   "app-test [app-name]",
+	Short: "Generate an end-to-end integration test for an app",
+	Long: `Generate an integration test that serves the app built by newApp in its main
+file, with the modules, middleware, and routes Run serves, on an ephemeral port
+and checks that each --endpoint returns 200. Without --endpoint, the test checks
+` + appHealthRoute + ` if the app serves health checks on its public router.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating integration test for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		mainPath := appMainPath(projectRoot, appName)
+		src, err := os.ReadFile(mainPath)
+		if err != nil {
+			log.Fatalf("Failed to read app '%s': %v", appName, err)
+		}
+		if !bytes.Contains(src, []byte("func newApp() *core.App {")) {
+			log.Fatalf("%s has no newApp function; move the core.New call, middleware, and routes from Run into func newApp() *core.App, as in apps created by this version of grob.", mainPath)
+		}
+
+		testPath := filepath.Join(projectRoot, "internal", appName, fmt.Sprintf("%s_integration_test.go", appName))
+		if _, err := os.Stat(testPath); err == nil {
+			log.Fatalf("%s already exists", testPath)
+		}
+
+		paths := appTestEndpoints
+		if len(paths) == 0 && bytes.Contains(src, []byte("health.RegisterRoutes(app.Router())")) {
+			paths = []string{appHealthRoute}
+		}
+		var endpoints []string
+		for _, e := range paths {
+			endpoints = append(endpoints, fmt.Sprintf("%q", e))
+		}
+
+		data["Endpoints"] = strings.Join(endpoints, ", ")
+		if err := useTestFramework(projectRoot, data, testFramework); err != nil {
+			log.Fatal(err)
+		}
+		utils.CreateFileFromTmpl(testPath, templates.AppTestTmpl, data)
+
+		log.Printf("Integration test created at %s.", testPath)
+		if len(endpoints) == 0 {
+			addNextStep("The test only boots the app; add the paths it should check to %s, or remove it and rerun with --endpoint.", testPath)
+		}
+		if data["TestFramework"] == utils.TestTestify && len(endpoints) > 0 {
+			addNextStep("Run 'go mod tidy' to fetch testify.")
+		}
+		addNextStep("Run it with 'go test -tags integration ./internal/%s/...'.", appName)
+	},
+}
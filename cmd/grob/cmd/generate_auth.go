@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateAuthCmd)
+}
+
+var generateAuthCmd = &cobra.Command{
+	Use:   "auth [app-name]",
+	Short: "Generate JWT authentication with a login endpoint for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating JWT authentication for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		authDir := filepath.Join(projectRoot, "internal", appName, "auth")
+		createPackageDir(authDir)
+		utils.CreateFileFromTmpl(filepath.Join(authDir, "auth.go"), templates.AuthTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(authDir, "middleware.go"), templates.AuthMiddlewareTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(authDir, "handler.go"), templates.AuthHandlerTmpl, data)
+
+		if err := utils.AddRequire(projectRoot, "github.com/golang-jwt/jwt/v5", "v5.2.1"); err != nil {
+			log.Fatalf("Failed to update go.mod: %v", err)
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/auth", data["ProjectName"], appName)
+		stmt := `auth.NewHandler(auth.StubAuthenticator{}).RegisterRoutes(app.Router().Group("/auth"))`
+		if err := utils.AddStatementToAppMain(appMainPath(projectRoot, appName), "", importPath, stmt); err != nil {
+			log.Fatalf("Failed to register the login route: %v", err)
+		}
+
+		log.Printf("Authentication created in %s; login is served at POST /auth/login.", authDir)
+		addNextStep("Set JWT_SECRET in the environment.")
+		addNextStep("Replace auth.StubAuthenticator with a real Authenticator.")
+		addNextStep("Protect routes with router.Use(auth.RequireAuth()).")
+		addNextStep("Run 'go mod tidy'.")
+	},
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	batchMax      int
+	batchMaxBytes int64
+)
+
+func init() {
+	generateBatchCmd.Flags().IntVar(&batchMax, "max", 100, "most operations accepted in one request")
+	generateBatchCmd.Flags().Int64Var(&batchMaxBytes, "max-bytes", 1<<20, "largest request body accepted, in bytes")
+	generateCmd.AddCommand(generateBatchCmd)
+}
+
+var generateBatchCmd = &cobra.Command{
+	Use:   "batch-endpoint [app-name] [module-name]",
+	Short: "Generate a POST /batch handler and service method for bulk operations on a module",
+	Long: `Generate a POST /batch handler on a module's controller that accepts a JSON
+array of create, update, and delete operations, and a ProcessBatch service
+method that runs them independently. The response holds one result per
+operation with its own status; it is 200 when all succeeded and 207
+Multi-Status when any failed. Requests over --max operations or --max-bytes
+are rejected with 413 before anything runs.`,
+	Example: `  grob generate batch-endpoint users profile --max 500`,
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating batch endpoint for module '%s' in app '%s'", moduleName, appName)
+
+		if batchMax <= 0 || batchMaxBytes <= 0 {
+			log.Fatal("--max and --max-bytes must be positive")
+		}
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		moduleName, title := data["ModuleName"], data["ModuleType"]
+		data["BatchMax"] = fmt.Sprint(batchMax)
+		data["BatchMaxBytes"] = fmt.Sprint(batchMaxBytes)
+
+		servicePath := filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName))
+		controllerPath := filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName))
+		for _, path := range []string{servicePath, controllerPath} {
+			if _, err := os.Stat(path); err != nil {
+				log.Fatalf("%s not found: the batch endpoint extends the module's controller and service", path)
+			}
+		}
+		// Answer in the style of the module's other handlers.
+		controller, err := os.ReadFile(controllerPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", controllerPath, err)
+		}
+		data["ResponseFormat"] = "raw"
+		if bytes.Contains(controller, []byte(strconv.Quote(data["ProjectName"]+"/pkg/response"))) {
+			data["ResponseFormat"] = "envelope"
+		}
+
+		batchPath := filepath.Join(moduleDir, fmt.Sprintf("%s.batch.go", moduleName))
+		if _, err := os.Stat(batchPath); err == nil {
+			log.Fatalf("%s already exists", batchPath)
+		}
+		// A controller switched to the service interface by 'grob generate cache'
+		// needs ProcessBatch on the interface, and the cached service forwards it.
+		data["BatchCached"] = ""
+		if _, err := os.Stat(filepath.Join(moduleDir, fmt.Sprintf("%s.cache.go", moduleName))); err == nil {
+			data["BatchCached"] = "true"
+		}
+		utils.CreateFileFromTmpl(batchPath, templates.BatchTmpl, data)
+		interfacePath := filepath.Join(moduleDir, fmt.Sprintf("%s.interface.go", moduleName))
+		if _, err := os.Stat(interfacePath); err == nil {
+			method := fmt.Sprintf("ProcessBatch(ctx context.Context, ops []%[1]sBatchOperation) []%[1]sBatchResult", title)
+			if _, err := utils.AddMethodToInterface(interfacePath, title+"ServiceInterface", method, "context"); err != nil {
+				log.Fatalf("Failed to add ProcessBatch to %sServiceInterface: %v", title, err)
+			}
+		}
+
+		ok, err := utils.AddRouteToController(controllerPath, title+"Controller", "POST", "/batch", "Batch")
+		if err != nil {
+			log.Fatalf("Failed to add the batch route: %v", err)
+		}
+		if !ok {
+			addNextStep("Route POST /batch to %sController.Batch; %s has no RegisterRoutes method.", title, controllerPath)
+		}
+
+		log.Printf("Batch endpoint created in %s and routed at POST /batch.", batchPath)
+		addNextStep("Implement the create, update, and delete cases in %sService.applyBatchOperation.", title)
+	},
+}
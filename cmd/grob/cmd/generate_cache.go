@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	cacheStore string
+	cacheTTL   time.Duration
+)
+
+func init() {
+	generateCacheCmd.Flags().StringVar(&cacheStore, "store", "memory", "cache backend: memory or redis")
+	generateCacheCmd.Flags().DurationVar(&cacheTTL, "ttl", 5*time.Minute, "how long results are cached")
+	generateCmd.AddCommand(generateCacheCmd)
+}
+
+var generateCacheCmd = &cobra.Command{
+	Use:   "cache [app-name] [module-name]",
+	Short: "Generate a caching decorator around a module's service",
+	Long: `Generate a caching decorator around a module's service.
+
+The service's exported methods are extracted into an interface, the controller is
+switched to depend on that interface, and the container is given a cached
+implementation that wraps the real service.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating cache for module '%s' in app '%s'", moduleName, appName)
+
+		if cacheStore != "memory" && cacheStore != "redis" {
+			log.Fatalf("Unknown cache store %q: use memory or redis", cacheStore)
+		}
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		moduleName, title := data["ModuleName"], data["ModuleType"]
+
+		cachePath := filepath.Join(moduleDir, fmt.Sprintf("%s.cache.go", moduleName))
+		if _, err := os.Stat(cachePath); err == nil {
+			log.Fatalf("%s already exists", cachePath)
+		}
+
+		methods, err := utils.ParseMethods(filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName)), title+"Service")
+		if err != nil {
+			log.Fatalf("Failed to read the %s service: %v", moduleName, err)
+		}
+		if len(methods) == 0 {
+			log.Fatalf("%sService has no exported methods to cache", title)
+		}
+
+		if err := ensureServiceInterface(moduleDir, data, methods); err != nil {
+			log.Fatal(err)
+		}
+
+		if err := ensureCachePackage(projectRoot, data, cacheStore == "redis"); err != nil {
+			log.Fatal(err)
+		}
+
+		imports := signatureImports(methods)
+		if cacheStore == "redis" {
+			imports = append(imports, `	"github.com/redis/go-redis/v9"`)
+		}
+		data["CacheImports"] = strings.Join(imports, "\n")
+		data["CacheStore"] = cacheStore
+		data["CacheTTL"] = durationExpr(cacheTTL)
+		data["CachedMethods"] = cachedMethods(moduleName, title, methods)
+		utils.CreateFileFromTmpl(cachePath, templates.CachedServiceTmpl, data)
+
+		// The controller and the gRPC server, whichever the module has, call the cached service.
+		for _, kind := range []string{"controller", "grpc"} {
+			path := filepath.Join(moduleDir, fmt.Sprintf("%s.%s.go", moduleName, kind))
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			if err := useServiceInterface(path, title); err != nil {
+				log.Fatalf("Failed to update %s: %v", path, err)
+			}
+		}
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+		if _, err := utils.AddProviderToModule(modulePath, "NewCached"+title+"Service"); err != nil {
+			log.Fatalf("Failed to register the cached service: %v", err)
+		}
+
+		log.Printf("Cached%sService created and provided as %sServiceInterface.", title, title)
+		if cacheStore == "redis" {
+			addNextStep("Provide a *redis.Client to the container (see --dependency on create-module).")
+			addNextStep("Run 'go mod tidy'.")
+		}
+	},
+}
+
+// ensureServiceInterface writes <module>.interface.go describing the service's methods,
+// unless the module already declares the interface.
+func ensureServiceInterface(moduleDir string, data map[string]string, methods []utils.Method) error {
+	moduleName := data["ModuleName"]
+	path := filepath.Join(moduleDir, fmt.Sprintf("%s.interface.go", moduleName))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	lines := make([]string, len(methods))
+	for i, m := range methods {
+		lines[i] = "\t" + m.Name + m.Signature()
+	}
+	data["InterfaceImports"] = strings.Join(signatureImports(methods), "\n")
+	data["InterfaceMethods"] = strings.Join(lines, "\n")
+	utils.CreateFileFromTmpl(path, templates.ServiceInterfaceTmpl, data)
+	return nil
+}
+
+// ensureCachePackage creates the shared pkg/cache store unless it already exists.
+func ensureCachePackage(projectRoot string, data map[string]string, withRedis bool) error {
+	dir := filepath.Join(projectRoot, "pkg", "cache")
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create cache package: %w", err)
+	}
+
+	files := map[string]string{"cache.go": templates.CacheStoreTmpl}
+	if withRedis {
+		files["redis.go"] = templates.CacheRedisTmpl
+		if err := utils.AddRequire(projectRoot, "github.com/redis/go-redis/v9", "v9.7.0"); err != nil {
+			return fmt.Errorf("failed to update go.mod: %w", err)
+		}
+	}
+	for name, tmpl := range files {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		utils.CreateFileFromTmpl(path, tmpl, data)
+	}
+	return nil
+}
+
+// signatureImports returns the import lines needed by the methods' signatures.
+func signatureImports(methods []utils.Method) []string {
+	seen := map[string]bool{}
+	var lines []string
+	for _, m := range methods {
+		for _, spec := range m.Imports {
+			if !seen[spec] {
+				seen[spec] = true
+				lines = append(lines, "\t"+spec)
+			}
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// cachedMethods generates the decorator's methods. Results are cached for methods
+// returning a value, or a value and an error; anything else is forwarded as-is.
+func cachedMethods(moduleName, title string, methods []utils.Method) string {
+	var sb strings.Builder
+	for _, m := range methods {
+		fmt.Fprintf(&sb, "\n// %s implements %sServiceInterface.\n", m.Name, title)
+		fmt.Fprintf(&sb, "func (s *Cached%sService) %s%s {\n", title, m.Name, m.Signature())
+
+		call := fmt.Sprintf("s.next.%s(%s)", m.Name, m.CallArgs())
+		returnsErr := len(m.Results) == 2 && m.Results[1] == "error"
+		if len(m.Results) != 1 && !returnsErr {
+			if len(m.Results) == 0 {
+				fmt.Fprintf(&sb, "\t%s\n}\n", call)
+			} else {
+				fmt.Fprintf(&sb, "\treturn %s\n}\n", call)
+			}
+			continue
+		}
+
+		keyParts := []string{fmt.Sprintf("%q", moduleName+"."+m.Name)}
+		for _, p := range m.Params {
+			if p.Type != "context.Context" {
+				keyParts = append(keyParts, p.Name)
+			}
+		}
+		fmt.Fprintf(&sb, "\tkey := cache.Key(%s)\n", strings.Join(keyParts, ", "))
+		fmt.Fprintf(&sb, "\tvar out %s\n", m.Results[0])
+		if returnsErr {
+			fmt.Fprintf(&sb, "\tif s.store.Get(key, &out) {\n\t\treturn out, nil\n\t}\n")
+			fmt.Fprintf(&sb, "\tout, err := %s\n\tif err != nil {\n\t\treturn out, err\n\t}\n", call)
+			fmt.Fprintf(&sb, "\ts.store.Set(key, out, s.ttl)\n\treturn out, nil\n}\n")
+		} else {
+			fmt.Fprintf(&sb, "\tif s.store.Get(key, &out) {\n\t\treturn out\n\t}\n")
+			fmt.Fprintf(&sb, "\tout = %s\n\ts.store.Set(key, out, s.ttl)\n\treturn out\n}\n", call)
+		}
+	}
+	return sb.String()
+}
+
+// useServiceInterface makes a controller or gRPC server depend on the service interface instead of the concrete type.
+func useServiceInterface(path, title string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	concrete := regexp.MustCompile(`\*` + title + `Service\b`)
+	out := concrete.ReplaceAll(src, []byte(title+"ServiceInterface"))
+	return os.WriteFile(path, out, utils.FileMode)
+}
+
+// durationExpr renders a duration as a readable Go expression, e.g. "5 * time.Minute".
+func durationExpr(d time.Duration) string {
+	for _, u := range []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	} {
+		if d >= u.unit && d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
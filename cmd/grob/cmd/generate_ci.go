@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var ciForce bool
+
+// ciProviders maps each provider to its config file, relative to the project root, and template.
+var ciProviders = map[string]struct {
+	path, tmpl string
+}{
+	"github": {filepath.Join(".github", "workflows", "ci.yml"), templates.CIGitHubTmpl},
+	"gitlab": {".gitlab-ci.yml", templates.CIGitLabTmpl},
+}
+
+func init() {
+	generateCICmd.Flags().BoolVar(&ciForce, "force", false, "overwrite an existing CI config")
+	generateCmd.AddCommand(generateCICmd)
+}
+
+var generateCICmd = &cobra.Command{
+	Use:       "ci [github|gitlab]",
+	Short:     "Generate a CI config that builds, vets, and tests the project",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"github", "gitlab"},
+	Run: func(cmd *cobra.Command, args []string) {
+		provider := ciProviders[args[0]]
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		data := utils.TemplateData(projectRoot)
+		if data["GoVersion"], err = utils.GoVersion(projectRoot); err != nil {
+			log.Fatalf("Could not read the Go version: %v", err)
+		}
+
+		path := filepath.Join(projectRoot, provider.path)
+		if err := os.MkdirAll(filepath.Dir(path), utils.DirMode); err != nil {
+			log.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		writeIfAbsent(path, provider.tmpl, data, ciForce)
+	},
+}
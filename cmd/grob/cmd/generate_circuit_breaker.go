@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	breakerFailureThreshold int
+	breakerOpenTimeout      time.Duration
+	breakerHalfOpenCalls    int
+)
+
+func init() {
+	generateCircuitBreakerCmd.Flags().IntVar(&breakerFailureThreshold, "failure-threshold", 5, "consecutive failures that open the breaker")
+	generateCircuitBreakerCmd.Flags().DurationVar(&breakerOpenTimeout, "open-timeout", 30*time.Second, "how long the breaker stays open before trial calls")
+	generateCircuitBreakerCmd.Flags().IntVar(&breakerHalfOpenCalls, "half-open-calls", 1, "trial calls allowed at once while half-open, all of which must succeed to close")
+	generateCmd.AddCommand(generateCircuitBreakerCmd)
+}
+
+var generateCircuitBreakerCmd = &cobra.Command{
+	Use:   "circuit-breaker [app-name] [module-name]",
+	Short: "Generate a circuit breaker around a module's outbound calls",
+	Long: `Generate a circuit breaker for a module that calls another service:
+
+  pkg/breaker                     the closed/open/half-open state machine and
+                                  an http.RoundTripper that uses it
+  <module>/<module>.breaker.go    <Module>Breaker with the module's settings
+
+After --failure-threshold consecutive failures the breaker opens and calls fail
+at once with breaker.ErrOpen. After --open-timeout it lets --half-open-calls
+trial calls through; it closes if they succeed and opens again if one fails.
+Transitions are logged from the breaker's OnStateChange hook, where metrics
+can be recorded as well.
+
+<Module>Breaker is provided to the container. For modules created with
+'grob generate module-client', the module's client is decorated so all its
+requests go through the breaker; network errors, 429s, and 5xx responses count
+as failures. Other modules wrap their calls in Execute.`,
+	Example: `  grob generate circuit-breaker api payments
+  grob generate circuit-breaker api payments --failure-threshold 3 --open-timeout 1m`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating circuit breaker for module '%s' in app '%s'", moduleName, appName)
+
+		if breakerFailureThreshold < 1 || breakerHalfOpenCalls < 1 {
+			log.Fatal("--failure-threshold and --half-open-calls must be at least 1")
+		}
+		if breakerOpenTimeout <= 0 {
+			log.Fatal("--open-timeout must be positive")
+		}
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		path := filepath.Join(moduleDir, fmt.Sprintf("%s.breaker.go", data["ModuleName"]))
+		if _, err := os.Stat(path); err == nil {
+			log.Fatalf("%s already exists", path)
+		}
+
+		// Modules from 'grob generate module-client' get their client decorated.
+		_, err := os.Stat(filepath.Join(moduleDir, fmt.Sprintf("%s.client.go", data["ModuleName"])))
+		withClient := err == nil
+		data["BreakerClient"] = ""
+		if withClient {
+			data["BreakerClient"] = "true"
+		}
+		data["BreakerFailureThreshold"] = strconv.Itoa(breakerFailureThreshold)
+		data["BreakerOpenTimeout"] = durationExpr(breakerOpenTimeout)
+		data["BreakerHalfOpenMaxCalls"] = strconv.Itoa(breakerHalfOpenCalls)
+
+		var files createdFiles
+		err = ensureBreakerPackage(projectRoot, data, &files)
+		if err == nil {
+			err = files.tmpl(path, templates.ModuleBreakerTmpl, data)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", data["ModuleName"]))
+		ctor := "New" + data["ModuleType"] + "Breaker"
+		ok, err := utils.AddProviderToModule(modulePath, ctor)
+		if err == nil && ok && withClient {
+			ok, err = utils.AddDecoratorToModule(modulePath, "with"+data["ModuleType"]+"Breaker")
+		}
+		if err != nil {
+			log.Fatalf("Failed to register %s: %v", ctor, err)
+		}
+		if !ok {
+			addProvideStep(ctor, modulePath)
+		}
+
+		if withClient {
+			stopRetrying(moduleDir, data)
+		}
+
+		log.Printf("%sBreaker created in %s.", data["ModuleType"], path)
+		if withClient {
+			addNextStep("Handle breaker.ErrOpen (errors.Is) where %sClient errors are returned, e.g. with 503 Service Unavailable.", data["ModuleType"])
+		} else {
+			addNextStep("Inject *%sBreaker where the module calls out, and wrap each call in Execute.", data["ModuleType"])
+		}
+	},
+}
+
+// ensureBreakerPackage creates the shared pkg/breaker.
+func ensureBreakerPackage(projectRoot string, data map[string]string, files *createdFiles) error {
+	dir := filepath.Join(projectRoot, "pkg", "breaker")
+	path := filepath.Join(dir, "breaker.go")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create breaker package: %w", err)
+	}
+	return files.tmpl(path, templates.BreakerTmpl, data)
+}
+
+// stopRetrying makes the module-client's own retries give up at once when the
+// breaker is open, instead of retrying ErrOpen with backoff. Clients that use
+// the app's retryclient are unaffected: the breaker wraps its transport, so it
+// sees a call only once its retries are done.
+func stopRetrying(moduleDir string, data map[string]string) {
+	path := filepath.Join(moduleDir, fmt.Sprintf("%s.client.go", data["ModuleName"]))
+	ok, err := utils.ReplaceInFile(path,
+		"return ctx.Err() == nil, err",
+		"return ctx.Err() == nil && !errors.Is(err, breaker.ErrOpen), err",
+		data["ProjectName"], "errors", data["ProjectName"]+"/pkg/breaker")
+	if err != nil {
+		log.Fatalf("Failed to update %s: %v", path, err)
+	}
+	if !ok {
+		addNextStep("Make %sClient stop retrying errors that wrap breaker.ErrOpen; %s was not changed.", data["ModuleType"], path)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	compressionAlgo    string
+	compressionMinSize int
+)
+
+func init() {
+	generateCompressionCmd.Flags().StringVar(&compressionAlgo, "algo", "gzip", "compression algorithm: gzip, or brotli with gzip for clients without it")
+	generateCompressionCmd.Flags().IntVar(&compressionMinSize, "min-size", 1024, "default size in bytes below which responses are not compressed")
+	generateCmd.AddCommand(generateCompressionCmd)
+}
+
+var generateCompressionCmd = &cobra.Command{
+	Use:   "response-compression [app-name]",
+	Short: "Generate a middleware compressing an app's responses with gzip or brotli",
+	Long: `Generate internal/<app>/compression, a gin middleware compressing responses
+for clients whose Accept-Encoding allows it, and add it to the app's router.
+
+  --algo gzip    gzip, from the standard library
+  --algo brotli  brotli, with github.com/andybalholm/brotli, falling back to
+                 gzip for clients that do not accept brotli
+
+Only text, JSON, JavaScript, XML, SVG, and WebAssembly responses are
+compressed, so images, archives, and other compressed formats are not
+compressed twice; the list is CompressibleTypes in the generated file.
+Responses smaller than <APP>_COMPRESSION_MIN_SIZE bytes (default --min-size)
+are sent as they are. Responses to HEAD, Range, and WebSocket requests,
+server-sent events, and responses a handler has encoded itself are left
+alone, and streamed responses are compressed as each part is flushed.`,
+	Example: `  grob generate response-compression api
+  grob generate response-compression api --algo brotli --min-size 512`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating response compression for app '%s'", appName)
+
+		if compressionAlgo != "gzip" && compressionAlgo != "brotli" {
+			log.Fatalf("Unknown algorithm %q: use gzip or brotli", compressionAlgo)
+		}
+		if compressionMinSize < 0 {
+			log.Fatal("--min-size must not be negative")
+		}
+
+		projectRoot, data := loadApp(appName)
+		dir := filepath.Join(projectRoot, "internal", appName, "compression")
+		createPackageDir(dir)
+		data["CompressionAlgo"] = compressionAlgo
+		data["CompressionMinSize"] = fmt.Sprint(compressionMinSize)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "compression.go"), templates.CompressionTmpl, data)
+
+		if compressionAlgo == "brotli" {
+			if err := utils.AddRequire(projectRoot, "github.com/andybalholm/brotli", "v1.1.1"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/compression", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "", importPath, "app.Router().Use(compression.Middleware())"); err != nil {
+			log.Fatalf("Failed to register the compression middleware: %v", err)
+		}
+
+		log.Printf("Compression middleware created in %s and registered.", dir)
+		if compressionAlgo == "brotli" {
+			addNextStep("Run 'go mod tidy' to download the brotli package.")
+		}
+		addNextStep("Remove any compression done by a proxy in front of the app, or this middleware, so responses are not compressed twice.")
+	},
+}
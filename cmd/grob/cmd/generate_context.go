@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateContextCmd)
+}
+
+var generateContextCmd = &cobra.Command{
+	Use:   "context [app-name]",
+	Short: "Generate typed request-context accessors and a request ID middleware for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating request context package for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		ctxDir := filepath.Join(projectRoot, "internal", appName, "reqctx")
+		createPackageDir(ctxDir)
+		utils.CreateFileFromTmpl(filepath.Join(ctxDir, "context.go"), templates.ReqCtxTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(ctxDir, "middleware.go"), templates.ReqCtxMiddlewareTmpl, data)
+
+		importPath := fmt.Sprintf("%s/internal/%s/reqctx", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "", importPath, "app.Router().Use(reqctx.RequestID())"); err != nil {
+			log.Fatalf("Failed to register request ID middleware: %v", err)
+		}
+
+		log.Printf("Request context package created in %s and middleware registered.", ctxDir)
+		addNextStep("Pass ctx.Request.Context() to services and read values with reqctx.RequestIDFromContext / reqctx.UserFromContext.")
+	},
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	deadLetterStore       string
+	deadLetterDriver      string
+	deadLetterTable       string
+	deadLetterMaxAttempts int
+	deadLetterBackoff     time.Duration
+	deadLetterMaxBackoff  time.Duration
+)
+
+func init() {
+	generateDeadLetterCmd.Flags().StringVar(&deadLetterStore, "store", "sql", "where dead letters are kept: sql (a table) or redis")
+	generateDeadLetterCmd.Flags().StringVar(&deadLetterDriver, "driver", "postgres", "SQL dialect of the sql store: postgres or mysql")
+	generateDeadLetterCmd.Flags().StringVar(&deadLetterTable, "table", "dead_letters", "table of the sql store")
+	generateDeadLetterCmd.Flags().IntVar(&deadLetterMaxAttempts, "max-attempts", 5, "how many times a message is handled before it is dead-lettered")
+	generateDeadLetterCmd.Flags().DurationVar(&deadLetterBackoff, "backoff", time.Second, "wait before the second attempt; it doubles after each attempt")
+	generateDeadLetterCmd.Flags().DurationVar(&deadLetterMaxBackoff, "max-backoff", 30*time.Second, "longest wait between attempts")
+	generateCmd.AddCommand(generateDeadLetterCmd)
+}
+
+var generateDeadLetterCmd = &cobra.Command{
+	Use:   "deadletter [app-name]",
+	Short: "Generate a dead-letter store for a worker app's failed messages, with inspection and replay",
+	Long: `Generate dead-letter handling for a worker app (see 'grob generate worker'):
+
+  pkg/deadletter                     the retry policy, the store, and an HTTP handler
+                                     to list, replay, and discard dead letters
+  migrations/<ts>_create_<table>     table migration, for the sql store
+  internal/<app>/deadletter.go       wiring for the worker
+
+The worker's handler is wrapped so a failing message is retried with backoff,
+up to --max-attempts times. A message that fails every attempt is stored with
+its payload and last error, then acknowledged so the broker stops redelivering
+it. Setting <APP>_DEADLETTER_ADDR serves the HTTP handler on that address;
+replaying a dead letter handles it again and removes it on success.`,
+	Example: `  grob generate deadletter worker
+  grob generate deadletter worker --store redis --max-attempts 3`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating dead-letter handling for app '%s'", appName)
+
+		if deadLetterStore != "sql" && deadLetterStore != "redis" {
+			log.Fatalf("Unknown store %q: use sql or redis", deadLetterStore)
+		}
+		driverName, ok := sqlDriverNames[deadLetterDriver]
+		if !ok {
+			log.Fatalf("Unknown driver %q: use postgres or mysql", deadLetterDriver)
+		}
+		if !sqlIdentifier.MatchString(deadLetterTable) {
+			log.Fatalf("Invalid table name %q", deadLetterTable)
+		}
+		if deadLetterMaxAttempts < 1 {
+			log.Fatal("--max-attempts must be at least 1")
+		}
+		if deadLetterBackoff <= 0 || deadLetterMaxBackoff < deadLetterBackoff {
+			log.Fatal("Invalid backoff: --backoff must be positive and no longer than --max-backoff")
+		}
+
+		projectRoot, data := loadApp(appName)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err == nil {
+			log.Fatalf("App '%s' is not a worker app; dead letters wrap the message handler of apps created with 'grob generate worker'.", appName)
+		}
+		workerPath := filepath.Join(projectRoot, "internal", appName, "deadletter.go")
+		if _, err := os.Stat(workerPath); err == nil {
+			log.Fatalf("%s already exists", workerPath)
+		}
+
+		data["DeadLetterStore"] = deadLetterStore
+		data["DeadLetterDriver"] = deadLetterDriver
+		data["DeadLetterDriverName"] = driverName
+		data["DeadLetterTable"] = deadLetterTable
+		data["DeadLetterMaxAttempts"] = strconv.Itoa(deadLetterMaxAttempts)
+		data["DeadLetterBackoff"] = durationExpr(deadLetterBackoff)
+		data["DeadLetterMaxBackoff"] = durationExpr(deadLetterMaxBackoff)
+
+		var files createdFiles
+		err := ensureDeadLetterPackage(projectRoot, data, &files)
+		if err == nil {
+			err = files.tmpl(workerPath, templates.WorkerDeadLetterTmpl, data)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		ok, err = utils.WrapCallArgument(mainPath, "Consume", 1, "withDeadLetters(ctx, %s)")
+		if err != nil {
+			log.Fatalf("Failed to wire dead letters into %s: %v", mainPath, err)
+		}
+		if !ok {
+			addNextStep("Wrap the handler you consume messages with in withDeadLetters(ctx, handler); %s has no Consume call.", mainPath)
+		}
+
+		log.Printf("Dead-letter handling added to app '%s'.", appName)
+		if deadLetterStore == "sql" {
+			addNextStep("Apply the migration in migrations/, set DEADLETTER_DATABASE_URL (or DATABASE_URL), and import the %q database/sql driver in internal/%s.", driverName, appName)
+		} else {
+			addNextStep("Set DEADLETTER_REDIS_URL and run 'go mod tidy'.")
+		}
+		addNextStep("Set %s_DEADLETTER_ADDR (e.g. localhost:8081) to list and replay dead letters at /deadletters.", data["EnvPrefix"])
+	},
+}
+
+// ensureDeadLetterPackage creates the shared pkg/deadletter, and the store the
+// app uses if the package lacks it.
+func ensureDeadLetterPackage(projectRoot string, data map[string]string, files *createdFiles) error {
+	dir := filepath.Join(projectRoot, "pkg", "deadletter")
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create deadletter package: %w", err)
+	}
+	for name, tmpl := range map[string]string{"deadletter.go": templates.DeadLetterTmpl, "http.go": templates.DeadLetterHTTPTmpl} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			continue
+		}
+		if err := files.tmpl(filepath.Join(dir, name), tmpl, data); err != nil {
+			return err
+		}
+	}
+
+	if data["DeadLetterStore"] == "redis" {
+		path := filepath.Join(dir, "redis.go")
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+		if err := utils.AddRequire(projectRoot, "github.com/redis/go-redis/v9", "v9.7.0"); err != nil {
+			return fmt.Errorf("failed to update go.mod: %w", err)
+		}
+		return files.tmpl(path, templates.DeadLetterRedisTmpl, data)
+	}
+
+	path := filepath.Join(dir, "sql.go")
+	if _, err := os.Stat(path); err == nil {
+		log.Printf("%s already exists; its table is kept.", path)
+		return nil
+	}
+	if err := files.tmpl(path, templates.DeadLetterSQLTmpl, data); err != nil {
+		return err
+	}
+	migrationsDir := filepath.Join(projectRoot, "migrations")
+	if err := os.MkdirAll(migrationsDir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+	migration := fmt.Sprintf("%s_create_%s", time.Now().UTC().Format("20060102150405"), data["DeadLetterTable"])
+	if err := files.tmpl(filepath.Join(migrationsDir, migration+".up.sql"), templates.DeadLetterMigrationUpTmpl, data); err != nil {
+		return err
+	}
+	return files.tmpl(filepath.Join(migrationsDir, migration+".down.sql"), templates.DeadLetterMigrationDownTmpl, data)
+}
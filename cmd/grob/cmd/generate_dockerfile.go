@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var dockerForce bool
+
+func init() {
+	generateDockerfileCmd.Flags().BoolVar(&dockerForce, "force", false, "overwrite an existing Dockerfile and .dockerignore")
+	generateCmd.AddCommand(generateDockerfileCmd)
+}
+
+var generateDockerfileCmd = &cobra.Command{
+	Use:   "dockerfile [app-name]",
+	Short: "Generate a multi-stage Dockerfile and a .dockerignore for the project",
+	Long: `Generate a multi-stage Dockerfile and a .dockerignore in the project root.
+In the shared layout the image runs internal/main.go; in the binaries layout
+pass the app to build its cmd/<app> binary.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+		cfg, err := utils.LoadConfig(projectRoot)
+		if err != nil {
+			log.Fatalf("Could not read %s: %v", utils.ConfigFileName, err)
+		}
+
+		data := utils.TemplateData(projectRoot)
+		if data["GoVersion"], err = utils.GoVersion(projectRoot); err != nil {
+			log.Fatalf("Could not read the Go version: %v", err)
+		}
+		data["BinaryName"] = path.Base(data["ProjectName"])
+		data["BuildPath"] = "internal"
+		if cfg.Binaries() {
+			if len(args) == 0 {
+				log.Fatal("This project uses the binaries layout; pass the app to build, e.g. 'grob generate dockerfile api'.")
+			}
+			data["BinaryName"] = args[0]
+			data["BuildPath"] = path.Join("cmd", args[0])
+		}
+
+		writeIfAbsent(filepath.Join(projectRoot, "Dockerfile"), templates.DockerfileTmpl, data, dockerForce)
+		writeIfAbsent(filepath.Join(projectRoot, ".dockerignore"), templates.DockerignoreTmpl, data, dockerForce)
+	},
+}
+
+// writeIfAbsent renders a template to path unless the file exists and force is false.
+func writeIfAbsent(path, tmpl string, data map[string]string, force bool) {
+	if _, err := os.Stat(path); err == nil && !force {
+		log.Printf("%s already exists; skipping (use --force to overwrite).", path)
+		return
+	}
+	utils.CreateFileFromTmpl(path, tmpl, data)
+	log.Printf("Wrote %s", path)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	enumString   bool
+	enumStringer bool
+)
+
+func init() {
+	generateEnumCmd.Flags().BoolVar(&enumString, "string", false, "back the enum with a string instead of an int")
+	generateEnumCmd.Flags().BoolVar(&enumStringer, "stringer", false, "add a go:generate stringer directive instead of a hand-written String method (int enums only)")
+	generateCmd.AddCommand(generateEnumCmd)
+}
+
+var generateEnumCmd = &cobra.Command{
+	Use:   "enum [app-name] [module-name] [name] [Value1,Value2,...]",
+	Short: "Generate a typed enum with parsing, validation, and JSON support",
+	Example: `  grob generate enum api users role Admin,Editor,Viewer
+  grob generate enum api orders status pending,paid,shipped --string`,
+	Args: cobra.ExactArgs(4),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName, name := args[0], args[1], args[2]
+		log.Printf("Generating enum '%s' in module '%s' of app '%s'", name, moduleName, appName)
+
+		if enumString && enumStringer {
+			log.Fatal("--stringer only applies to int enums")
+		}
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		moduleName = data["ModuleName"]
+
+		if err := addEnumData(data, name, strings.Split(args[3], ",")); err != nil {
+			log.Fatal(err)
+		}
+
+		path := filepath.Join(moduleDir, strings.ToLower(name)+".go")
+		if _, err := os.Stat(path); err == nil {
+			log.Fatalf("%s already exists", path)
+		}
+		utils.CreateFileFromTmpl(path, templates.EnumTmpl, data)
+
+		log.Printf("Enum %s created in %s.", data["EnumType"], path)
+		if enumStringer {
+			addNextStep("Run 'go generate' with golang.org/x/tools/cmd/stringer installed to create its String method.")
+		}
+	},
+}
+
+// addEnumData validates the enum name and values and adds the template data that declares them.
+func addEnumData(data map[string]string, name string, values []string) error {
+	typeName := strings.Title(name)
+	if !token.IsIdentifier(typeName) {
+		return fmt.Errorf("enum name %q is not a valid Go identifier", name)
+	}
+
+	var consts, list, cases []string
+	seen := map[string]bool{}
+	for i, v := range values {
+		v = strings.TrimSpace(v)
+		constName := typeName + strings.Title(v)
+		if !token.IsIdentifier(v) || !token.IsIdentifier(constName) {
+			return fmt.Errorf("enum value %q is not a valid Go identifier", v)
+		}
+		if seen[constName] {
+			return fmt.Errorf("enum value %q is declared twice", v)
+		}
+		seen[constName] = true
+
+		switch {
+		case enumString:
+			consts = append(consts, fmt.Sprintf("\t%s %s = %q", constName, typeName, v))
+		case i == 0:
+			// Start at 1 so the zero value is never a valid member.
+			consts = append(consts, fmt.Sprintf("\t%s %s = iota + 1", constName, typeName))
+		default:
+			consts = append(consts, "\t"+constName)
+		}
+		list = append(list, constName)
+		cases = append(cases, fmt.Sprintf("\tcase %s:\n\t\treturn %q", constName, v))
+	}
+
+	data["EnumName"] = strings.ToLower(typeName[:1]) + typeName[1:]
+	data["EnumType"] = typeName
+	data["EnumKind"] = "int"
+	data["EnumZero"] = "0"
+	if enumString {
+		data["EnumKind"] = "string"
+		data["EnumZero"] = `""`
+	}
+	data["EnumStringer"] = ""
+	if enumStringer {
+		data["EnumStringer"] = "true"
+	}
+	data["EnumConsts"] = strings.Join(consts, "\n")
+	data["EnumValues"] = strings.Join(list, ", ")
+	data["EnumCases"] = strings.Join(cases, "\n")
+	return nil
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	envConfigSpec     string
+	envConfigRequired []string
+	envConfigForce    bool
+)
+
+func init() {
+	generateEnvConfigCmd.Flags().StringVar(&envConfigSpec, "spec", "", "YAML file declaring variables under \"vars\" (name, type, required, default, description)")
+	generateEnvConfigCmd.Flags().StringSliceVar(&envConfigRequired, "required", nil, "variables that must be set, e.g. DATABASE_URL,JWT_SECRET")
+	generateEnvConfigCmd.Flags().BoolVar(&envConfigForce, "force", false, "regenerate pkg/config/config.go if it exists")
+	generateCmd.AddCommand(generateEnvConfigCmd)
+}
+
+var generateEnvConfigCmd = &cobra.Command{
+	Use:   "env-config [app-name]",
+	Short: "Generate a typed Config loaded and validated from the environment, and provide it to an app",
+	Long: `Generate pkg/config with a Config struct holding every environment variable the
+project reads, found by scanning for os.Getenv and os.LookupEnv calls and env
+helpers such as getenv("QUEUE_URL", "..."). Variables declared with --spec are
+added to the scan, and their settings take precedence.
+
+LoadConfig fills Config from the environment and reports every missing required
+variable and malformed value at once. ConfigModule calls it when the app starts
+and provides *config.Config to the container.`,
+	Example: `  grob generate env-config api --required DATABASE_URL
+  grob generate env-config api --spec env.yaml --force`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating environment config for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+		ignore, err := utils.LoadIgnore(projectRoot)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", utils.IgnoreFileName, err)
+		}
+		vars, err := utils.ScanEnvVars(projectRoot, ignore, "pkg/config")
+		if err != nil {
+			log.Fatalf("Failed to scan the project: %v", err)
+		}
+		if envConfigSpec != "" {
+			declared, err := utils.LoadEnvSpec(envConfigSpec)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			vars = mergeEnvVars(vars, declared)
+			sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+		}
+		if err := markRequired(vars, envConfigRequired); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if len(vars) == 0 {
+			log.Fatal("No environment variables found. Declare them in a spec with --spec.")
+		}
+
+		fields, imports, err := configFields(vars)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		data["ConfigFields"] = fields
+		data["ConfigImports"] = imports
+
+		dir := filepath.Join(projectRoot, "pkg", "config")
+		configPath := filepath.Join(dir, "config.go")
+		if _, err := os.Stat(configPath); err == nil && !envConfigForce {
+			log.Fatalf("%s already exists; use --force to regenerate it.", configPath)
+		}
+		if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+			log.Fatalf("Failed to create config package: %v", err)
+		}
+		utils.CreateFileFromTmpl(configPath, templates.EnvConfigTmpl, data)
+		for name, tmpl := range map[string]string{"load.go": templates.EnvConfigLoadTmpl, "module.go": templates.EnvConfigModuleTmpl} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				utils.CreateFileFromTmpl(filepath.Join(dir, name), tmpl, data)
+			}
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/pkg/config"
+		modules, err := utils.ParseAppModules(mainPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", mainPath, err)
+		}
+		registered := false
+		for _, m := range modules {
+			if m.ImportPath == importPath {
+				registered = true
+			}
+		}
+		if registered {
+			log.Printf("ConfigModule is already registered in app '%s'.", appName)
+		} else if err := utils.AddModuleToAppMain(mainPath, importPath, "config", "Config"); err != nil {
+			log.Fatalf("Failed to register ConfigModule: %v", err)
+		}
+
+		log.Printf("Config with %d variables created in %s and ConfigModule registered in app '%s'.", len(vars), dir, appName)
+		var files []string
+		seen := map[string]bool{}
+		for _, v := range vars {
+			for _, f := range v.Files {
+				if !seen[f] {
+					seen[f] = true
+					files = append(files, f)
+				}
+			}
+		}
+		sort.Strings(files)
+		if len(files) > 0 {
+			addNextStep("Inject *config.Config and replace the environment reads in:\n  %s", strings.Join(files, "\n  "))
+		}
+	},
+}
+
+// mergeEnvVars adds the declared variables to the scanned ones. A declared
+// variable replaces a scanned one of the same name, keeping the files it was found in.
+func mergeEnvVars(scanned, declared []utils.EnvVar) []utils.EnvVar {
+	index := map[string]int{}
+	for i, v := range scanned {
+		index[v.Name] = i
+	}
+	for _, v := range declared {
+		if i, ok := index[v.Name]; ok {
+			v.Files = scanned[i].Files
+			scanned[i] = v
+			continue
+		}
+		index[v.Name] = len(scanned)
+		scanned = append(scanned, v)
+	}
+	return scanned
+}
+
+// markRequired sets Required on the named variables, which must exist.
+func markRequired(vars []utils.EnvVar, names []string) error {
+	for _, name := range names {
+		found := false
+		for i := range vars {
+			if vars[i].Name == name {
+				vars[i].Required = true
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("--required %s: no such variable is read by the project or declared with --spec", name)
+		}
+	}
+	return nil
+}
+
+// configFields renders the Config struct fields and the imports their types need.
+func configFields(vars []utils.EnvVar) (fields, imports string, err error) {
+	var lines []string
+	seen := map[string]string{}
+	needsTime := false
+	for _, v := range vars {
+		if err := utils.ValidateEnvVar(v); err != nil {
+			return "", "", err
+		}
+		name := utils.GoName(v.Name)
+		if other, ok := seen[name]; ok {
+			return "", "", fmt.Errorf("%s and %s both become the field %s", other, v.Name, name)
+		}
+		seen[name] = v.Name
+
+		comment := v.Description
+		switch {
+		case comment != "":
+		case len(v.Files) > 0:
+			comment = "read in " + strings.Join(v.Files, ", ")
+		default:
+			comment = "declared in the env spec"
+		}
+		tag := fmt.Sprintf("env:%q", v.Name)
+		if v.Required {
+			tag += ` required:"true"`
+		} else if v.Default != "" {
+			tag += " envDefault:" + strconv.Quote(v.Default)
+		}
+		goType := utils.EnvTypes[v.Type]
+		needsTime = needsTime || v.Type == "duration"
+
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines,
+			fmt.Sprintf("\t// %s is %s, %s.", name, v.Name, strings.TrimSuffix(comment, ".")),
+			fmt.Sprintf("\t%s %s `%s`", name, goType, tag))
+	}
+	if needsTime {
+		imports = "\t\"time\""
+	}
+	return strings.Join(lines, "\n"), imports, nil
+}
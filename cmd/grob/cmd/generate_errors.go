@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateErrorsCmd)
+}
+
+var generateErrorsCmd = &cobra.Command{
+	Use:   "errors [app-name]",
+	Short: "Generate a typed error package and error-handling middleware for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating error package for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		errorsDir := filepath.Join(projectRoot, "internal", appName, "errors")
+		createPackageDir(errorsDir)
+		utils.CreateFileFromTmpl(filepath.Join(errorsDir, "errors.go"), templates.ErrorsTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(errorsDir, "middleware.go"), templates.ErrorsMiddlewareTmpl, data)
+
+		importPath := fmt.Sprintf("%s/internal/%s/errors", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "apperrors", importPath, "app.Router().Use(apperrors.Middleware())"); err != nil {
+			log.Fatalf("Failed to register error middleware: %v", err)
+		}
+
+		log.Printf("Error package created in %s and middleware registered.", errorsDir)
+		addNextStep("Return errors from handlers with ctx.Error(apperrors.NotFound(\"...\")).")
+	},
+}
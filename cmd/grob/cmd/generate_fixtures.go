@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	fixturesModel string
+	fixturesRows  int
+)
+
+func init() {
+	generateFixturesCmd.Flags().StringVar(&fixturesModel, "model", "", "model whose table is seeded (default: the only model in the module)")
+	generateFixturesCmd.Flags().IntVar(&fixturesRows, "rows", 2, "number of sample rows in the fixture file")
+	generateFixturesCmd.Flags().StringVar(&testFramework, "test-framework", "", `assertions of the generated tests: "stdlib" or "testify" (default from .grobrc, else stdlib)`)
+	generateCmd.AddCommand(generateFixturesCmd)
+}
+
+var generateFixturesCmd = &cobra.Command{
+	Use:   "fixtures [app-name] [module-name]",
+	Short: "Generate YAML fixtures and a loader that seeds a module's table for integration tests",
+	Long: `Generate test database fixtures for a module's model, using the table of the
+repository from 'grob generate sql-repository':
+
+  pkg/fixtures                        loader shared by every module
+  <module>/testdata/<table>.yaml      sample rows to edit
+  <module>/<module>.fixtures_test.go  LoadFixtures(t, db) for the module's tests
+  <module>/<model>.testdata_test.go   test loading the fixtures into TEST_DATABASE_URL
+
+LoadFixtures empties the tables of testdata/*.yaml and inserts their rows in one
+transaction, before the test; the tables are emptied again when it ends. Tests
+whose code accepts a *sql.Tx can use fixtures.LoadTx instead, which seeds inside
+a transaction that is rolled back, leaving the database untouched.`,
+	Example: `  grob generate fixtures users profile
+  grob generate fixtures shop orders --model OrderItem --rows 5`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating fixtures for module '%s' in app '%s'", moduleName, appName)
+
+		if fixturesRows < 1 {
+			log.Fatal("--rows must be at least 1")
+		}
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		model, err := findModel(moduleDir, fixturesModel)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		repoPath := filepath.Join(moduleDir, fmt.Sprintf("%s.repository.go", strings.ToLower(model.Name)))
+		if _, err := os.Stat(repoPath); err != nil {
+			log.Fatalf("%s has no SQL repository; run 'grob generate sql-repository %s %s --model %s' first.", model.Name, appName, moduleName, model.Name)
+		}
+		repo, err := utils.ParseSQLRepository(repoPath, model.Name)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", repoPath, err)
+		}
+
+		table := utils.UnquoteTable(repo.Table)
+		fixturePath := filepath.Join(moduleDir, "testdata", strings.ReplaceAll(table, ".", "_")+".yaml")
+		if _, err := os.Stat(fixturePath); err == nil {
+			log.Fatalf("%s already exists", fixturePath)
+		}
+
+		rows, skipped := fixtureRows(model, repo, fixturesRows)
+		data["FixtureTable"] = table
+		// Quoted identifiers of a schema-qualified table would not be a plain YAML scalar.
+		data["FixtureTableYAML"] = repo.Table
+		if table != repo.Table {
+			data["FixtureTableYAML"] = "'" + repo.Table + "'"
+		}
+		data["FixtureRows"] = rows
+		data["FixtureNote"] = ""
+		if len(skipped) > 0 {
+			data["FixtureNote"] = fmt.Sprintf("No sample values for %s; add them if the columns are NOT NULL without a default.", strings.Join(skipped, ", "))
+		}
+		data["FixtureDialect"] = "Postgres"
+		data["FixtureDriverName"] = sqlDriverNames["postgres"]
+		if repo.Placeholder == "?" {
+			data["FixtureDialect"] = "MySQL"
+			data["FixtureDriverName"] = sqlDriverNames["mysql"]
+		}
+		data["ModelName"] = model.Name
+		data["FixtureTableSQL"] = goStringContent(repo.Table)
+
+		var files createdFiles
+		err = useTestFramework(projectRoot, data, testFramework)
+		if err == nil {
+			err = ensureFixturesPackage(projectRoot, data, &files)
+		}
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(fixturePath), utils.DirMode)
+		}
+		if err == nil {
+			err = files.tmpl(fixturePath, templates.FixtureFileTmpl, data)
+		}
+		helperPath := filepath.Join(moduleDir, fmt.Sprintf("%s.fixtures_test.go", data["ModuleName"]))
+		if _, statErr := os.Stat(helperPath); err == nil && statErr != nil {
+			err = files.tmpl(helperPath, templates.FixturesHelperTmpl, data)
+		}
+		testPath := filepath.Join(moduleDir, fmt.Sprintf("%s.testdata_test.go", strings.ToLower(model.Name)))
+		if _, statErr := os.Stat(testPath); err == nil && statErr != nil {
+			err = files.tmpl(testPath, templates.FixturesTestTmpl, data)
+		}
+		if err == nil {
+			driver := sqlDriverModules[strings.ToLower(data["FixtureDialect"])]
+			err = utils.AddRequire(projectRoot, driver[0], driver[1])
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Fixtures for table %s created in %s.", table, fixturePath)
+		driver := strings.ToLower(data["FixtureDialect"])
+		fetch := "gopkg.in/yaml.v3 and the " + driver + " driver"
+		if data["TestFramework"] == utils.TestTestify {
+			fetch = "gopkg.in/yaml.v3, the " + driver + " driver, and testify"
+		}
+		addNextStep("Run 'go mod tidy' to fetch %s.", fetch)
+		addNextStep("Run the %s fixtures test with TEST_DATABASE_URL set to a test database.", model.Name)
+		addNextStep("Call LoadFixtures(t, db) in the module's integration tests, with db opened against a test database: the fixture tables are emptied.")
+	},
+}
+
+// ensureFixturesPackage creates the shared pkg/fixtures loader.
+func ensureFixturesPackage(projectRoot string, data map[string]string, files *createdFiles) error {
+	dir := filepath.Join(projectRoot, "pkg", "fixtures")
+	path := filepath.Join(dir, "fixtures.go")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create fixtures package: %w", err)
+	}
+	if err := utils.AddRequire(projectRoot, "gopkg.in/yaml.v3", "v3.0.1"); err != nil {
+		return fmt.Errorf("failed to update go.mod: %w", err)
+	}
+	return files.tmpl(path, templates.FixturesTmpl, data)
+}
+
+// fixtureRows returns the YAML rows of a fixture file with sample values for
+// the model's columns, and the columns it has no sample value for. Integer
+// keys are left to the database and the soft-delete column to its NULL
+// default.
+func fixtureRows(model utils.Model, repo utils.SQLRepository, n int) (string, []string) {
+	var b strings.Builder
+	var skipped []string
+	for i := 1; i <= n; i++ {
+		first := true
+		for _, f := range model.Fields {
+			if f.Name == utils.SoftDeleteField || (f.Column == repo.IDColumn && integerTypes[strings.TrimPrefix(f.Type, "*")]) {
+				continue
+			}
+			value, ok := fixtureValue(f, f.Column == repo.IDColumn, utils.UnquoteTable(repo.Table), i)
+			if !ok {
+				if i == 1 {
+					skipped = append(skipped, f.Column)
+				}
+				continue
+			}
+			prefix := "    "
+			if first {
+				prefix = "  - "
+				first = false
+			}
+			fmt.Fprintf(&b, "%s%s: %s\n", prefix, f.Column, value)
+		}
+		if first {
+			b.WriteString("  - {}\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), skipped
+}
+
+// fixtureValue returns the YAML value of a field in the i-th sample row.
+func fixtureValue(f utils.ModelField, key bool, table string, i int) (string, bool) {
+	t := strings.TrimPrefix(f.Type, "*")
+	switch {
+	case key && t == "string":
+		return fmt.Sprintf("%q", fmt.Sprintf("%s-%d", table, i)), true
+	case t == "string":
+		return fmt.Sprintf("%q", fmt.Sprintf("%s %d", f.Column, i)), true
+	case t == "bool":
+		return fmt.Sprint(i%2 == 1), true
+	case integerTypes[t], t == "int8", t == "int16", t == "uint8", t == "uint16":
+		return fmt.Sprint(i), true
+	case t == "float32", t == "float64":
+		return fmt.Sprintf("%d.5", i), true
+	case t == "time.Time":
+		return fmt.Sprintf("%q", fmt.Sprintf("2024-01-%02d 12:00:00", (i-1)%28+1)), true
+	}
+	return "", false
+}
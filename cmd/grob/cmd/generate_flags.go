@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateFlagsCmd)
+}
+
+var generateFlagsCmd = &cobra.Command{
+	Use:   "feature-flag [app-name]",
+	Short: "Generate an environment-driven feature flag package for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating feature flags for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		dir := filepath.Join(projectRoot, "internal", appName, "flags")
+		createPackageDir(dir)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "flags.go"), templates.FeatureFlagsTmpl, data)
+
+		importPath := fmt.Sprintf("%s/internal/%s/flags", data["ProjectName"], appName)
+		if err := utils.AddModuleToAppMain(appMainPath(projectRoot, appName), importPath, "flags", "Flags"); err != nil {
+			log.Fatalf("Failed to register FlagsModule: %v", err)
+		}
+
+		log.Printf("Feature flags created in %s and FlagsModule registered.", dir)
+		addNextStep("Enable flags with %s_FEATURES=a,b or %s_FEATURE_<NAME>=true, and inject *flags.Flags where you need them.", data["EnvPrefix"], data["EnvPrefix"])
+	},
+}
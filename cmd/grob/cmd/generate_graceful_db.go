@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	dbDriver      string
+	dbMaxOpen     int
+	dbMaxIdle     int
+	dbMaxLifetime time.Duration
+)
+
+func init() {
+	generateGracefulDBCmd.Flags().StringVar(&dbDriver, "driver", "postgres", "database driver: postgres or mysql")
+	generateGracefulDBCmd.Flags().IntVar(&dbMaxOpen, "max-open", 25, "default maximum number of open connections")
+	generateGracefulDBCmd.Flags().IntVar(&dbMaxIdle, "max-idle", 10, "default maximum number of idle connections")
+	generateGracefulDBCmd.Flags().DurationVar(&dbMaxLifetime, "max-lifetime", 30*time.Minute, "default maximum time a connection is reused")
+	generateCmd.AddCommand(generateGracefulDBCmd)
+}
+
+var generateGracefulDBCmd = &cobra.Command{
+	Use:   "graceful-db [app-name]",
+	Short: "Generate a tuned *sql.DB provider that pings on startup and closes on shutdown",
+	Long: `Generate internal/<app>/database, providing the app's *sql.DB connection pool:
+
+  - the pool connects to <APP>_DATABASE_URL, or DATABASE_URL, and its size and
+    connection lifetime are read from <APP>_DB_MAX_OPEN_CONNS,
+    <APP>_DB_MAX_IDLE_CONNS, and <APP>_DB_CONN_MAX_LIFETIME, defaulting to
+    --max-open, --max-idle, and --max-lifetime;
+  - on startup the database is pinged, with retries and backoff, so the app
+    waits for a database that is still starting and stops if it never answers;
+  - the pool is closed once the server has shut down.
+
+DatabaseModule is registered in the app's main.go, with 'defer database.Start()()'
+before the server starts. Repositories from 'grob generate sql-repository' and
+the health check's database probe use the *sql.DB it provides.`,
+	Example: `  grob generate graceful-db shop
+  grob generate graceful-db shop --driver mysql --max-open 50 --max-idle 50 --max-lifetime 5m`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating database provider for app '%s'", appName)
+
+		driverName, ok := sqlDriverNames[dbDriver]
+		if !ok {
+			log.Fatalf("Unknown driver %q: use postgres or mysql", dbDriver)
+		}
+		switch {
+		case dbMaxOpen < 1:
+			log.Fatal("--max-open must be at least 1")
+		case dbMaxIdle < 0 || dbMaxIdle > dbMaxOpen:
+			log.Fatalf("--max-idle must be between 0 and --max-open %d", dbMaxOpen)
+		case dbMaxLifetime < 0:
+			log.Fatal("--max-lifetime must not be negative")
+		}
+
+		projectRoot, data := loadApp(appName)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Fatalf("App '%s' has no dependency injection container to provide the database with", appName)
+		}
+		dir := filepath.Join(projectRoot, "internal", appName, "database")
+		if _, err := os.Stat(dir); err == nil {
+			log.Fatalf("%s already exists", dir)
+		}
+
+		data["DBDriver"] = dbDriver
+		data["DBDriverName"] = driverName
+		data["DBMaxOpen"] = fmt.Sprint(dbMaxOpen)
+		data["DBMaxIdle"] = fmt.Sprint(dbMaxIdle)
+		data["DBMaxLifetime"] = durationExpr(dbMaxLifetime)
+		data["DBMaxLifetimeText"] = dbMaxLifetime.String()
+
+		var files createdFiles
+		err := os.Mkdir(dir, utils.DirMode)
+		if err == nil {
+			err = files.tmpl(filepath.Join(dir, "database.go"), templates.DatabaseTmpl, data)
+		}
+		if err == nil {
+			driver := sqlDriverModules[dbDriver]
+			err = utils.AddRequire(projectRoot, driver[0], driver[1])
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/internal/" + appName + "/database"
+		if err := utils.AddModuleToAppMain(mainPath, importPath, "database", "Database"); err != nil {
+			log.Fatalf("Failed to register DatabaseModule: %v", err)
+		}
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "defer database.Start()()"); err != nil {
+			log.Fatalf("Failed to start the database in %s: %v", mainPath, err)
+		}
+
+		log.Printf("DatabaseModule created and registered in app '%s'.", appName)
+		addNextStep("Remove any other *sql.DB provider from the app's modules; the container accepts only one.")
+		addNextStep("Set %s_DATABASE_URL or DATABASE_URL, and run 'go mod tidy'.", data["EnvPrefix"])
+	},
+}
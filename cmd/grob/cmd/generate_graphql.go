@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateGraphQLCmd)
+}
+
+var generateGraphQLCmd = &cobra.Command{
+	Use:   "graphql [app-name]",
+	Short: "Generate a gqlgen-based GraphQL skeleton for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating GraphQL skeleton for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		graphDir := filepath.Join(projectRoot, "internal", appName, "graph")
+		createPackageDir(graphDir)
+		utils.CreateFileFromTmpl(filepath.Join(graphDir, "schema.graphqls"), templates.GraphQLSchemaTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(graphDir, "gqlgen.yml"), templates.GqlgenConfigTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(graphDir, "resolver.go"), templates.GraphQLResolverTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(graphDir, "handler.go"), templates.GraphQLHandlerTmpl, data)
+
+		for _, req := range [][2]string{
+			{"github.com/99designs/gqlgen", "v0.17.55"},
+			{"github.com/vektah/gqlparser/v2", "v2.5.18"},
+		} {
+			if err := utils.AddRequire(projectRoot, req[0], req[1]); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/graph", data["ProjectName"], appName)
+		if err := utils.AddStatementToAppMain(appMainPath(projectRoot, appName), "", importPath, "graph.RegisterRoutes(app.Router(), graph.NewResolver())"); err != nil {
+			log.Fatalf("Failed to mount the GraphQL handler: %v", err)
+		}
+
+		log.Printf("GraphQL skeleton created in %s and mounted at /graphql.", graphDir)
+		addNextStep("Run 'go mod tidy'.")
+		addNextStep("Run 'go generate ./internal/%s/graph'.", appName)
+	},
+}
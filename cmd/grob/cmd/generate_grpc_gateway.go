@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	gatewayPrefix   string
+	gatewayGrpcAddr string
+)
+
+func init() {
+	generateGrpcGatewayCmd.Flags().StringVar(&gatewayPrefix, "prefix", "/v1", "route prefix the REST gateway is mounted under")
+	generateGrpcGatewayCmd.Flags().StringVar(&gatewayGrpcAddr, "grpc-addr", "localhost:9090", "default address of the gRPC server")
+	generateCmd.AddCommand(generateGrpcGatewayCmd)
+}
+
+var generateGrpcGatewayCmd = &cobra.Command{
+	Use:   "grpc-gateway [app-name]",
+	Short: "Generate a gRPC server with a grpc-gateway REST proxy mounted on an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating gRPC gateway for app '%s'", appName)
+
+		prefix := "/" + strings.Trim(gatewayPrefix, "/")
+		if prefix == "/" {
+			log.Fatalf("Invalid --prefix %q: the gateway needs its own route prefix", gatewayPrefix)
+		}
+
+		projectRoot, data := loadApp(appName)
+		data["GrpcService"] = utils.GoName(appName) + "Service"
+		data["GatewayPrefix"] = prefix
+		data["GrpcAddr"] = gatewayGrpcAddr
+
+		gatewayDir := filepath.Join(projectRoot, "internal", appName, "gateway")
+		createPackageDir(gatewayDir)
+		for _, dir := range []string{"proto", "pb"} {
+			createPackageDir(filepath.Join(gatewayDir, dir))
+		}
+		utils.CreateFileFromTmpl(filepath.Join(gatewayDir, "gateway.go"), templates.GrpcGatewayTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(gatewayDir, "gateway.yaml"), templates.GrpcGatewayConfigTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(gatewayDir, "proto", appName+".proto"), templates.GrpcProtoTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(gatewayDir, "pb", "doc.go"), templates.GrpcPbDocTmpl, data)
+
+		for _, req := range [][2]string{
+			{"google.golang.org/grpc", "v1.58.3"},
+			{"github.com/grpc-ecosystem/grpc-gateway/v2", "v2.18.1"},
+		} {
+			if err := utils.AddRequire(projectRoot, req[0], req[1]); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/gateway", data["ProjectName"], appName)
+		if err := utils.AddStatementToAppMain(appMainPath(projectRoot, appName), "", importPath, "defer gateway.Mount(app.Router())()"); err != nil {
+			log.Fatalf("Failed to wire the gateway: %v", err)
+		}
+
+		log.Printf("gRPC gateway created in %s and mounted under %s.", gatewayDir, prefix)
+		addNextStep("Install protoc-gen-go, protoc-gen-go-grpc and protoc-gen-grpc-gateway.")
+		addNextStep("Run 'go generate ./internal/%s/gateway' to generate the pb package.", appName)
+		addNextStep("Append pb.Register%sServer and pb.Register%sHandlerFromEndpoint to gateway.Services and gateway.Handlers.", data["GrpcService"], data["GrpcService"])
+		addNextStep("Run 'go mod tidy'.")
+	},
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var healthDeps []string
+
+// healthDepKeys maps each supported --health-deps value to the template key enabling its check.
+var healthDepKeys = map[string]string{
+	"db":    "HealthDB",
+	"redis": "HealthRedis",
+}
+
+func init() {
+	generateHealthCmd.Flags().StringSliceVar(&healthDeps, "health-deps", nil, "dependencies /readyz pings, resolved from the container: db, redis")
+	generateCmd.AddCommand(generateHealthCmd)
+}
+
+var generateHealthCmd = &cobra.Command{
+	Use:   "healthcheck [app-name]",
+	Short: "Generate /healthz and /readyz endpoints for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating health checks for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+		for _, key := range healthDepKeys {
+			data[key] = ""
+		}
+		for _, dep := range healthDeps {
+			key, ok := healthDepKeys[dep]
+			if !ok {
+				log.Fatalf("Unknown health dependency %q: use db or redis", dep)
+			}
+			data[key] = "true"
+		}
+		data["HealthDeps"] = strings.Join(healthDeps, ",")
+
+		healthDir := filepath.Join(projectRoot, "internal", appName, "health")
+		createPackageDir(healthDir)
+		utils.CreateFileFromTmpl(filepath.Join(healthDir, "health.go"), templates.HealthTmpl, data)
+
+		if data["HealthRedis"] != "" {
+			if err := utils.AddRequire(projectRoot, "github.com/redis/go-redis/v9", "v9.7.0"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := fmt.Sprintf("%s/internal/%s/health", data["ProjectName"], appName)
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "health.RegisterRoutes("+opsRouter(mainPath)+")"); err != nil {
+			log.Fatalf("Failed to wire health checks: %v", err)
+		}
+		if data["HealthDeps"] != "" {
+			if err := utils.AddModuleToAppMain(mainPath, importPath, "health", "Health"); err != nil {
+				log.Fatalf("Failed to register HealthModule: %v", err)
+			}
+		}
+
+		log.Printf("Health checks created in %s: GET /healthz and GET /readyz.", healthDir)
+		addNextStep("Add your own readiness checks with health.Register.")
+	},
+}
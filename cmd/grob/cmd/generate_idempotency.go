@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	idempotencyStore string
+	idempotencyTTL   time.Duration
+)
+
+func init() {
+	generateIdempotencyCmd.Flags().StringVar(&idempotencyStore, "store", "memory", "where keys and responses are kept: memory or redis")
+	generateIdempotencyCmd.Flags().DurationVar(&idempotencyTTL, "ttl", 24*time.Hour, "how long a completed response is replayed for its key")
+	generateCmd.AddCommand(generateIdempotencyCmd)
+}
+
+var generateIdempotencyCmd = &cobra.Command{
+	Use:   "idempotency [app-name]",
+	Short: "Generate an Idempotency-Key middleware that replays responses to retried writes",
+	Long: `Generate a middleware for POST, PUT, PATCH and DELETE requests carrying an
+Idempotency-Key header. The first request with a key runs and its response is
+stored; retries with the same key get the stored response instead of running
+again, and retries that arrive while it is still running get 409 Conflict.
+
+Use --store redis when the app runs on more than one instance.`,
+	Example: `  grob generate idempotency api --store redis --ttl 48h`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating idempotency middleware for app '%s'", appName)
+
+		if idempotencyStore != "memory" && idempotencyStore != "redis" {
+			log.Fatalf("Unknown store %q: use memory or redis", idempotencyStore)
+		}
+		if idempotencyTTL <= 0 {
+			log.Fatal("--ttl must be positive")
+		}
+
+		projectRoot, data := loadApp(appName)
+		data["IdempotencyStore"] = idempotencyStore
+		data["IdempotencyTTL"] = durationExpr(idempotencyTTL)
+
+		dir := filepath.Join(projectRoot, "internal", appName, "idempotency")
+		createPackageDir(dir)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "idempotency.go"), templates.IdempotencyTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "store.go"), templates.IdempotencyStoreTmpl, data)
+		if idempotencyStore == "redis" {
+			if err := utils.AddRequire(projectRoot, "github.com/redis/go-redis/v9", "v9.7.0"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/idempotency", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "", importPath, "app.Router().Use(idempotency.Middleware(idempotency.NewStore(), idempotency.DefaultTTL))"); err != nil {
+			log.Fatalf("Failed to register idempotency middleware: %v", err)
+		}
+
+		log.Printf("Idempotency middleware created in %s and registered for every POST, PUT, PATCH and DELETE route.", dir)
+		if idempotencyStore == "redis" {
+			addNextStep("Set %s_IDEMPOTENCY_URL to your Redis server and run 'go mod tidy'.", data["EnvPrefix"])
+		}
+		addNextStep("Have clients send a unique Idempotency-Key header with each write they may retry.")
+	},
+}
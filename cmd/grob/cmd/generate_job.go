@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	generateJobCmd.Flags().DurationVar(&appInterval, "interval", time.Minute, "default interval between runs")
+	generateCmd.AddCommand(generateJobCmd)
+}
+
+var generateJobCmd = &cobra.Command{
+	Use:   "background-job [app-name]",
+	Short: "Generate a periodic background job app managed like the other apps",
+	Long: `Generate a background job app that runs on an interval. It is registered as an
+AppRunner, so it starts and shuts down together with the project's HTTP apps.
+This is a shorthand for 'grob create-app [app-name] --type job'.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Creating new background job: %s", appName)
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		appType = "job"
+		files, err := createApp(projectRoot, appName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportCreated(projectRoot, files)
+	},
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var mapperModel string
+
+func init() {
+	generateMapperCmd.Flags().StringVar(&mapperModel, "model", "", "model to map (default: the only model in the module)")
+	generateCmd.AddCommand(generateMapperCmd)
+}
+
+var generateMapperCmd = &cobra.Command{
+	Use:   "mapper [app-name] [module-name]",
+	Short: "Generate conversions between a module's model and its request/response DTOs",
+	Long: `Generate To<Model>Response and From<Model>Request functions for a module's model.
+The module must declare <Model>Request and <Model>Response structs. Fields are
+matched by name and type; fields without a match are left as TODOs and reported.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating mapper for module '%s' in app '%s'", moduleName, appName)
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		model, err := findModel(moduleDir, mapperModel)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		path := filepath.Join(moduleDir, strings.ToLower(model.Name)+".mapper.go")
+		if _, err := os.Stat(path); err == nil {
+			log.Fatalf("%s already exists", path)
+		}
+
+		types, err := moduleStructs(moduleDir)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		request, okReq := types[model.Name+"Request"]
+		response, okResp := types[model.Name+"Response"]
+		if !okReq || !okResp {
+			log.Fatalf("%s needs both %sRequest and %sResponse structs to map between", moduleDir, model.Name, model.Name)
+		}
+
+		var todos []string
+		toFields, missing := mapFields(model, response, "m")
+		todos = append(todos, missing...)
+		fromFields, missing := mapFields(request, model, "r")
+		todos = append(todos, missing...)
+
+		data["ModelName"] = model.Name
+		data["MapperToFields"] = strings.Join(toFields, "\n")
+		data["MapperFromFields"] = strings.Join(fromFields, "\n")
+		utils.CreateFileFromTmpl(path, templates.MapperTmpl, data)
+
+		log.Printf("Mapper created in %s.", path)
+		for _, todo := range todos {
+			addNextStep("Fill in the TODO in %s: %s.", filepath.Base(path), todo)
+		}
+	},
+}
+
+// moduleStructs returns the struct types declared in a module's Go files, by name.
+func moduleStructs(moduleDir string) (map[string]utils.Model, error) {
+	paths, err := filepath.Glob(filepath.Join(moduleDir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]utils.Model{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		models, err := utils.ParseModels(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			types[m.Name] = m
+		}
+	}
+	return types, nil
+}
+
+// mapFields returns the keyed fields of a dst composite literal copied from the
+// variable src of type from, matching fields by name and type. Unmatched dst
+// fields become TODO comments, which are also returned for reporting.
+func mapFields(from, dst utils.Model, src string) (lines, todos []string) {
+	fields := map[string]utils.ModelField{}
+	for _, f := range from.Fields {
+		fields[f.Name] = f
+	}
+	for _, f := range dst.Fields {
+		sf, ok := fields[f.Name]
+		var todo string
+		switch {
+		case !ok:
+			todo = fmt.Sprintf("set %s.%s; %s has no %s field", dst.Name, f.Name, from.Name, f.Name)
+		case sf.Type != f.Type:
+			todo = fmt.Sprintf("set %s.%s; %s.%s is %s, not %s", dst.Name, f.Name, from.Name, f.Name, sf.Type, f.Type)
+		default:
+			lines = append(lines, fmt.Sprintf("\t\t%s: %s.%s,", f.Name, src, f.Name))
+			continue
+		}
+		lines = append(lines, "\t\t// TODO: "+todo+".")
+		todos = append(todos, todo)
+	}
+	return lines, todos
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateMetricsCmd)
+}
+
+var generateMetricsCmd = &cobra.Command{
+	Use:   "metrics [app-name]",
+	Short: "Generate Prometheus request metrics and a /metrics endpoint for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating metrics for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		metricsDir := filepath.Join(projectRoot, "internal", appName, "metrics")
+		createPackageDir(metricsDir)
+		utils.CreateFileFromTmpl(filepath.Join(metricsDir, "metrics.go"), templates.MetricsTmpl, data)
+
+		if err := utils.AddRequire(projectRoot, "github.com/prometheus/client_golang", "v1.20.5"); err != nil {
+			log.Fatalf("Failed to update go.mod: %v", err)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := fmt.Sprintf("%s/internal/%s/metrics", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(mainPath, "", importPath, "app.Router().Use(metrics.Middleware())"); err != nil {
+			log.Fatalf("Failed to wire metrics: %v", err)
+		}
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "metrics.RegisterRoutes("+opsRouter(mainPath)+")"); err != nil {
+			log.Fatalf("Failed to wire metrics: %v", err)
+		}
+
+		log.Printf("Metrics created in %s and exposed at /metrics.", metricsDir)
+		addNextStep("Run 'go mod tidy' to download the Prometheus client.")
+	},
+}
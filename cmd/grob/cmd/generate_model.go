@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	modelFields     []string
+	modelStructTags string
+)
+
+func init() {
+	generateModelCmd.Flags().StringSliceVar(&modelFields, "fields", nil, `struct fields as NAME:TYPE, e.g. "id:int,email:string,created_at:time.Time"`)
+	generateModelCmd.Flags().StringVar(&modelStructTags, "struct-tags", "", `casing of JSON tags: "snake", "camel", or "pascal" (default from .grobrc, else snake)`)
+	generateCmd.AddCommand(generateModelCmd)
+}
+
+var generateModelCmd = &cobra.Command{
+	Use:     "model [app-name] [module-name] [model-name]",
+	Short:   "Generate a model struct with JSON tags in a module",
+	Example: `  grob generate model api users user --fields id:int,email:string,avatar_url:string --struct-tags camel`,
+	Args:    cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName, modelName := args[0], args[1], args[2]
+		log.Printf("Generating model '%s' in module '%s' of app '%s'", modelName, moduleName, appName)
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		moduleName = data["ModuleName"]
+
+		if modelStructTags != "" {
+			data["StructTags"] = modelStructTags
+		}
+		switch data["StructTags"] {
+		case utils.TagSnake, utils.TagCamel, utils.TagPascal:
+		default:
+			log.Fatalf("Unknown struct tag style %q: use snake, camel, or pascal", data["StructTags"])
+		}
+
+		data["ModelName"] = utils.GoName(modelName)
+		if !token.IsIdentifier(data["ModelName"]) {
+			log.Fatalf("Model name %q is not a valid Go identifier", modelName)
+		}
+		if err := addModelFields(data, modelFields); err != nil {
+			log.Fatal(err)
+		}
+
+		path := filepath.Join(moduleDir, fmt.Sprintf("%s.model.go", strings.ToLower(data["ModelName"])))
+		if _, err := os.Stat(path); err == nil {
+			log.Fatalf("%s already exists", path)
+		}
+		utils.CreateFileFromTmpl(path, templates.ModelTmpl, data)
+		log.Printf("Model %s created in %s.", data["ModelName"], path)
+	},
+}
+
+// modelTypePackages maps the package names allowed in --fields types to their import paths.
+var modelTypePackages = map[string]string{
+	"time":  "time",
+	"json":  "encoding/json",
+	"sql":   "database/sql",
+	"url":   "net/url",
+	"big":   "math/big",
+	"netip": "net/netip",
+}
+
+// addModelFields parses NAME:TYPE pairs into struct field declarations tagged in data["StructTags"] style.
+func addModelFields(data map[string]string, values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("--fields is required, e.g. --fields id:int,name:string")
+	}
+
+	var fields []string
+	imports := map[string]bool{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		name, typ, ok := strings.Cut(v, ":")
+		name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+		if !ok || name == "" || typ == "" {
+			return fmt.Errorf("invalid field %q: use NAME:TYPE", v)
+		}
+
+		goName := utils.GoName(name)
+		if !token.IsIdentifier(goName) {
+			return fmt.Errorf("field name %q is not a valid Go identifier", name)
+		}
+		if seen[goName] {
+			return fmt.Errorf("field %s is declared twice", goName)
+		}
+		seen[goName] = true
+
+		expr, err := parser.ParseExpr(typ)
+		if err != nil {
+			return fmt.Errorf("field %s: invalid type %q", name, typ)
+		}
+		var unknown error
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok {
+					path, known := modelTypePackages[pkg.Name]
+					if !known {
+						unknown = fmt.Errorf("field %s: cannot resolve the import for package %s", name, pkg.Name)
+					}
+					imports[path] = true
+				}
+				return false
+			}
+			return true
+		})
+		if unknown != nil {
+			return unknown
+		}
+
+		tag := utils.TagName(goName, data["StructTags"])
+		fields = append(fields, fmt.Sprintf("\t%s %s `json:%q`", goName, typ, tag))
+	}
+
+	var importLines []string
+	for path := range imports {
+		importLines = append(importLines, fmt.Sprintf("\t%q", path))
+	}
+	sort.Strings(importLines)
+	data["ModelImports"] = strings.Join(importLines, "\n")
+	data["ModelFields"] = strings.Join(fields, "\n")
+	return nil
+}
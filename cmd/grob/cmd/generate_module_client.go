@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"log"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateModuleClientCmd.Flags().StringVar(&moduleClientURL, "base-url", "", "default base URL of the external API (required)")
+	generateModuleClientCmd.Flags().StringVar(&moduleRespFormat, "response-format", "", `JSON response style of generated handlers: "raw" or "envelope" (default from .grobrc, else raw)`)
+	generateModuleClientCmd.Flags().BoolVar(&moduleRetryClient, "retry-client", false, "send requests through the app's retryclient (see generate retry-client), creating it if needed")
+	generateModuleClientCmd.MarkFlagRequired("base-url")
+	generateCmd.AddCommand(generateModuleClientCmd)
+}
+
+var generateModuleClientCmd = &cobra.Command{
+	Use:   "module-client [app-name] [module-name]",
+	Short: "Generate a module that wraps an external HTTP API",
+	Long: `Generate a module whose service wraps an outbound HTTP client for a third-party API.
+
+The client reads its base URL and API key from <APP>_<MODULE>_BASE_URL and
+<APP>_<MODULE>_API_KEY, retries transient failures with backoff, and is
+provided to the container alongside the module's service and controller.
+With --retry-client it leaves retries to the app's shared retryclient instead.`,
+	Example: `  grob generate module-client api payments --base-url https://api.stripe.com`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Creating API client module '%s' in app '%s'", moduleName, appName)
+
+		if u, err := url.Parse(moduleClientURL); err != nil || u.Scheme == "" || u.Host == "" {
+			log.Fatalf("Invalid --base-url %q: use an absolute URL such as https://api.example.com", moduleClientURL)
+		}
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		moduleKind = "client"
+		files, err := createModule(projectRoot, appName, moduleName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportCreated(projectRoot, files)
+
+		pkgName, _ := utils.ModuleNames(moduleName)
+		prefix := utils.EnvPrefix(appName) + "_" + utils.EnvPrefix(pkgName)
+		addNextStep("Set %s_API_KEY to authenticate, and %s_BASE_URL to point at another environment.", prefix, prefix)
+	},
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+// tenantField is the model field holding a row's tenant ID.
+const tenantField = "TenantID"
+
+var (
+	tenantStrategy string
+	tenantHeader   string
+	tenantDomain   string
+	tenantModule   string
+	tenantModel    string
+	tenantColumn   string
+)
+
+// headerName matches the header names --header accepts.
+var headerName = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+func init() {
+	generateMultitenancyCmd.Flags().StringVar(&tenantStrategy, "strategy", "header", "where the tenant ID is read from: header or subdomain")
+	generateMultitenancyCmd.Flags().StringVar(&tenantHeader, "header", "X-Tenant-ID", "request header holding the tenant ID with --strategy header")
+	generateMultitenancyCmd.Flags().StringVar(&tenantDomain, "domain", "", "domain tenants are subdomains of with --strategy subdomain, e.g. example.com")
+	generateMultitenancyCmd.Flags().StringVar(&tenantModule, "module", "", "module whose SQL repository gets a tenant-scoped decorator")
+	generateMultitenancyCmd.Flags().StringVar(&tenantModel, "model", "", "model of the repository to scope (default: the only model in the module)")
+	generateMultitenancyCmd.Flags().StringVar(&tenantColumn, "column", "tenant_id", "column holding each row's tenant ID")
+	generateCmd.AddCommand(generateMultitenancyCmd)
+}
+
+var generateMultitenancyCmd = &cobra.Command{
+	Use:   "multitenancy [app-name]",
+	Short: "Generate tenant resolution middleware and tenant-scoped repositories for an app",
+	Long: `Generate internal/<app>/tenant with a middleware that reads the tenant ID of
+each request from a header or its subdomain and stores it in the request
+context. Requests without a valid tenant get 400 Bad Request. TenantModule
+provides a tenant.Accessor to the container for services that need the tenant.
+
+With --module, the module's repository from 'grob generate sql-repository' gets
+a Tenant<Model>Repository decorator whose queries are all restricted to the
+tenant in the context, and the model gets a TenantID field if it has none.
+Run the command again with another --module to scope more repositories.`,
+	Example: `  grob generate multitenancy api --strategy header
+  grob generate multitenancy api --strategy subdomain --domain example.com
+  grob generate multitenancy api --module orders`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating multitenancy for app '%s'", appName)
+
+		switch tenantStrategy {
+		case "header":
+			if !headerName.MatchString(tenantHeader) {
+				log.Fatalf("Invalid header name %q", tenantHeader)
+			}
+		case "subdomain":
+			tenantDomain = strings.ToLower(strings.Trim(tenantDomain, "."))
+			if strings.ContainsAny(tenantDomain, `"/: `) {
+				log.Fatalf("Invalid domain %q", tenantDomain)
+			}
+		default:
+			log.Fatalf("Unknown strategy %q: use header or subdomain", tenantStrategy)
+		}
+		if !sqlIdentifier.MatchString(tenantColumn) {
+			log.Fatalf("Invalid column name %q", tenantColumn)
+		}
+
+		projectRoot, data := loadApp(appName)
+		// Check the module's repository before writing anything.
+		var scoped *tenantRepository
+		if tenantModule != "" {
+			scoped = findTenantRepository(appName, tenantModule)
+		}
+		data["TenantStrategy"] = tenantStrategy
+		data["TenantHeader"] = tenantHeader
+		data["TenantDomain"] = tenantDomain
+
+		dir := filepath.Join(projectRoot, "internal", appName, "tenant")
+		if _, err := os.Stat(dir); err == nil {
+			log.Printf("%s already exists; keeping its middleware.", dir)
+		} else {
+			createPackageDir(dir)
+			utils.CreateFileFromTmpl(filepath.Join(dir, "tenant.go"), templates.TenantTmpl, data)
+			utils.CreateFileFromTmpl(filepath.Join(dir, "middleware.go"), templates.TenantMiddlewareTmpl, data)
+
+			mainPath := appMainPath(projectRoot, appName)
+			importPath := fmt.Sprintf("%s/internal/%s/tenant", data["ProjectName"], appName)
+			if err := utils.AddModuleToAppMain(mainPath, importPath, "tenant", "Tenant"); err != nil {
+				log.Fatalf("Failed to register TenantModule: %v", err)
+			}
+			if err := utils.AddMiddlewareToAppMain(mainPath, "", importPath, "app.Router().Use(tenant.Middleware())"); err != nil {
+				log.Fatalf("Failed to register tenant middleware: %v", err)
+			}
+			log.Printf("Tenant middleware created in %s and registered ahead of the app's routes.", dir)
+			if tenantStrategy == "header" {
+				addNextStep("Send the tenant ID in the %s header; routes in tenant.PublicPaths need none.", tenantHeader)
+			} else {
+				addNextStep("Point each tenant's subdomain at the app; routes in tenant.PublicPaths need no tenant.")
+			}
+		}
+
+		if scoped == nil {
+			addNextStep("Scope a module's SQL repository with 'grob generate multitenancy %s --module <module>'.", appName)
+			return
+		}
+		scopeRepository(scoped)
+	},
+}
+
+// tenantRepository is a module's SQL repository to scope to the tenant.
+type tenantRepository struct {
+	data      map[string]string
+	moduleDir string
+	model     utils.Model
+	repo      utils.SQLRepository
+	idField   string
+	// path is the file of the tenant-scoped decorator.
+	path string
+}
+
+// findTenantRepository finds the SQL repository of a module and checks that it
+// can be scoped to the tenant, without writing anything.
+func findTenantRepository(appName, moduleName string) *tenantRepository {
+	_, data, moduleDir := loadModule(appName, moduleName)
+	model, err := findModel(moduleDir, tenantModel)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	repoPath := filepath.Join(moduleDir, fmt.Sprintf("%s.repository.go", strings.ToLower(model.Name)))
+	if _, err := os.Stat(repoPath); err != nil {
+		log.Fatalf("%s has no SQL repository; run 'grob generate sql-repository %s %s --model %s' first.", model.Name, appName, moduleName, model.Name)
+	}
+	repo, err := utils.ParseSQLRepository(repoPath, model.Name)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", repoPath, err)
+	}
+	path := filepath.Join(moduleDir, fmt.Sprintf("%s.tenant_repository.go", strings.ToLower(model.Name)))
+	if _, err := os.Stat(path); err == nil {
+		log.Fatalf("%s already exists", path)
+	}
+	idField := ""
+	for _, f := range model.Fields {
+		if f.Column == repo.IDColumn {
+			idField = f.Name
+		}
+	}
+	if idField == "" {
+		log.Fatalf("%s has no field for the %s column its repository uses as key", model.Name, repo.IDColumn)
+	}
+	return &tenantRepository{data: data, moduleDir: moduleDir, model: model, repo: repo, idField: idField, path: path}
+}
+
+// scopeRepository adds a tenant-scoped decorator for a module's SQL repository.
+func scopeRepository(t *tenantRepository) {
+	data, moduleDir, model, repo, idField, path := t.data, t.moduleDir, t.model, t.repo, t.idField, t.path
+
+	field := fmt.Sprintf("%s string `json:%q`", tenantField, utils.TagName(tenantField, data["StructTags"]))
+	if tenantColumn != utils.TagName(tenantField, utils.TagSnake) {
+		field = fmt.Sprintf("%s string `json:%q db:%q`", tenantField, utils.TagName(tenantField, data["StructTags"]), tenantColumn)
+	}
+	added, err := utils.AddStructField(model.Path, model.Name, tenantField, field, "")
+	if err != nil {
+		log.Fatalf("Failed to add %s to %s: %v", tenantField, model.Name, err)
+	}
+	if added {
+		log.Printf("Added %s to %s in %s.", tenantField, model.Name, model.Path)
+		if model, err = findModel(moduleDir, model.Name); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
+	column := ""
+	for _, f := range model.Fields {
+		if f.Name == tenantField {
+			column = f.Column
+		}
+	}
+
+	data["ModelName"] = model.Name
+	setRepoTable(data, repo.Table)
+	data["RepoDriver"] = "postgres"
+	if repo.Placeholder == "?" {
+		data["RepoDriver"] = "mysql"
+	}
+	if err := addRepositoryQueries(data, model, idField); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := addTenantQueries(data, model, idField, column); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	utils.CreateFileFromTmpl(path, templates.TenantRepositoryTmpl, data)
+
+	modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", data["ModuleName"]))
+	ctor := "NewTenant" + model.Name + "Repository"
+	ok, err := utils.AddProviderToModule(modulePath, ctor)
+	if err != nil {
+		log.Fatalf("Failed to register %s: %v", ctor, err)
+	}
+	if !ok {
+		addProvideStep(ctor, modulePath)
+	}
+
+	log.Printf("Tenant%sRepository created in %s.", model.Name, path)
+	addNextStep("Add the tenant column and an index to %s, e.g. ALTER TABLE %s ADD COLUMN %s VARCHAR(64) NOT NULL; CREATE INDEX %s_%s_idx ON %s (%s);",
+		repo.Table, repo.Table, column, strings.ReplaceAll(repo.Table, ".", "_"), column, repo.Table, column)
+	addNextStep("Inject *Tenant%sRepository instead of *%sRepository wherever requests read or write %s rows.", model.Name, model.Name, repo.Table)
+}
+
+// addTenantQueries fills in the parts of the tenant-scoped queries that differ
+// from the repository's: the tenant condition, and an UPDATE that leaves the
+// tenant column alone.
+func addTenantQueries(data map[string]string, model utils.Model, idField, column string) error {
+	placeholder := func(n int) string {
+		if data["RepoDriver"] == "mysql" {
+			return "?"
+		}
+		return fmt.Sprintf("$%d", n)
+	}
+
+	var set, args []string
+	for _, f := range model.Fields {
+		if f.Name == idField || f.Name == tenantField || f.Name == utils.SoftDeleteField {
+			continue
+		}
+		set = append(set, fmt.Sprintf("%s = %s", f.Column, placeholder(len(set)+1)))
+		args = append(args, "m."+f.Name)
+	}
+	if len(set) == 0 {
+		return fmt.Errorf("model %s has no fields besides %s and %s", model.Name, idField, tenantField)
+	}
+	args = append(args, "m."+idField)
+
+	data["TenantField"] = tenantField
+	data["TenantColumn"] = column
+	data["TenantSoftDelete"], _ = softDeleteColumn(model)
+	data["TenantP1"] = placeholder(1)
+	data["TenantP2"] = placeholder(2)
+	data["TenantUpdateSet"] = strings.Join(set, ", ")
+	data["TenantUpdateArgs"] = strings.Join(args, ", ")
+	data["TenantUpdateIDPlaceholder"] = placeholder(len(set) + 1)
+	data["TenantUpdateTenantPlaceholder"] = placeholder(len(set) + 2)
+	return nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	outboxRelay    string
+	outboxTable    string
+	outboxDriver   string
+	outboxInterval time.Duration
+)
+
+// sqlDriverNames maps each --driver value to the database/sql driver name the
+// relay opens its connection with.
+var sqlDriverNames = map[string]string{"postgres": "pgx", "mysql": "mysql"}
+
+// sqlDriverModules maps each --driver value to the module providing its
+// database/sql driver, at the version generated code is written against.
+var sqlDriverModules = map[string][2]string{
+	"postgres": {"github.com/jackc/pgx/v5", "v5.7.5"},
+	"mysql":    {"github.com/go-sql-driver/mysql", "v1.9.3"},
+}
+
+func init() {
+	generateOutboxCmd.Flags().StringVar(&outboxRelay, "relay", "", "name of the relay app to create (default: <app-name>relay)")
+	generateOutboxCmd.Flags().StringVar(&outboxTable, "table", "outbox", "database table events are recorded in")
+	generateOutboxCmd.Flags().StringVar(&outboxDriver, "driver", "postgres", "SQL dialect: postgres or mysql")
+	generateOutboxCmd.Flags().DurationVar(&outboxInterval, "interval", time.Second, "default interval at which the relay polls the outbox")
+	generateCmd.AddCommand(generateOutboxCmd)
+}
+
+var generateOutboxCmd = &cobra.Command{
+	Use:   "outbox [app-name]",
+	Short: "Generate a transactional outbox: migration, repository, and a relay app that publishes events",
+	Long: `Generate a transactional outbox for an app:
+
+  pkg/outbox                     Repository to record events in a transaction, and the Relay
+  migrations/<ts>_create_<table> table migration (up and down)
+  internal/<relay>               relay app that polls the outbox and publishes events
+
+OutboxModule is registered in the app so services can inject *outbox.Repository.
+If pkg/queue exists (see 'grob generate queue'), the relay publishes to it.`,
+	Example: `  grob generate outbox api --driver mysql --interval 500ms`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		relayName := outboxRelay
+		if relayName == "" {
+			relayName = appName + "relay"
+		}
+		log.Printf("Generating outbox for app '%s' with relay app '%s'", appName, relayName)
+
+		driverName, ok := sqlDriverNames[outboxDriver]
+		if !ok {
+			log.Fatalf("Unknown driver %q: use postgres or mysql", outboxDriver)
+		}
+		if !sqlIdentifier.MatchString(outboxTable) {
+			log.Fatalf("Invalid table name %q", outboxTable)
+		}
+		if outboxInterval <= 0 {
+			log.Fatalf("Invalid interval %s: it must be positive", outboxInterval)
+		}
+
+		projectRoot, data := loadApp(appName)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Fatalf("App '%s' has no dependency injection container; the outbox must be written by an HTTP app.", appName)
+		}
+		relayDir := filepath.Join(projectRoot, "internal", relayName)
+		if _, err := os.Stat(relayDir); err == nil {
+			log.Fatalf("%s already exists", relayDir)
+		}
+
+		migration := fmt.Sprintf("%s_create_%s", time.Now().UTC().Format("20060102150405"), outboxTable)
+		data["OutboxTable"] = outboxTable
+		data["OutboxDriver"] = outboxDriver
+		data["OutboxDriverName"] = driverName
+		data["OutboxMigration"] = migration
+		data["OutboxInterval"] = durationExpr(outboxInterval)
+		data["OutboxIntervalText"] = outboxInterval.String()
+		data["OutboxQueue"] = ""
+		if _, err := os.Stat(filepath.Join(projectRoot, "pkg", "queue", "queue.go")); err == nil {
+			data["OutboxQueue"] = "true"
+		}
+
+		dir := filepath.Join(projectRoot, "pkg", "outbox")
+		if _, err := os.Stat(dir); err == nil {
+			log.Printf("%s already exists; reusing it.", dir)
+		} else {
+			if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+				log.Fatalf("Failed to create outbox package: %v", err)
+			}
+			utils.CreateFileFromTmpl(filepath.Join(dir, "outbox.go"), templates.OutboxTmpl, data)
+			utils.CreateFileFromTmpl(filepath.Join(dir, "relay.go"), templates.OutboxRelayTmpl, data)
+			utils.CreateFileFromTmpl(filepath.Join(dir, "module.go"), templates.OutboxModuleTmpl, data)
+
+			migrationsDir := filepath.Join(projectRoot, "migrations")
+			if err := os.MkdirAll(migrationsDir, utils.DirMode); err != nil {
+				log.Fatalf("Failed to create migrations directory: %v", err)
+			}
+			utils.CreateFileFromTmpl(filepath.Join(migrationsDir, migration+".up.sql"), templates.OutboxMigrationUpTmpl, data)
+			utils.CreateFileFromTmpl(filepath.Join(migrationsDir, migration+".down.sql"), templates.OutboxMigrationDownTmpl, data)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/pkg/outbox"
+		modules, err := utils.ParseAppModules(mainPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", mainPath, err)
+		}
+		registered := false
+		for _, m := range modules {
+			if m.ImportPath == importPath {
+				registered = true
+			}
+		}
+		if registered {
+			log.Printf("OutboxModule is already registered in app '%s'.", appName)
+		} else if err := utils.AddModuleToAppMain(mainPath, importPath, "outbox", "Outbox"); err != nil {
+			log.Fatalf("Failed to register OutboxModule: %v", err)
+		}
+
+		relayData := make(map[string]string, len(data))
+		for k, v := range data {
+			relayData[k] = v
+		}
+		relayData["AppName"] = relayName
+		relayData["EnvPrefix"] = utils.EnvPrefix(relayName)
+		if err := os.Mkdir(relayDir, utils.DirMode); err != nil {
+			log.Fatalf("Failed to create relay app directory: %v", err)
+		}
+		utils.CreateFileFromTmpl(filepath.Join(relayDir, relayName+"_main.go"), templates.OutboxRelayMainTmpl, relayData)
+		utils.CreateFileFromTmpl(filepath.Join(relayDir, "publisher.go"), templates.OutboxPublisherTmpl, relayData)
+		var files createdFiles
+		if err := registerApp(projectRoot, data["ProjectName"], relayName, &files); err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Outbox created in %s and OutboxModule registered in app '%s'.", dir, appName)
+		addNextStep("Apply the migration in migrations/ and set DATABASE_URL.")
+		addNextStep("Import the %q database/sql driver in internal/%s.", driverName, relayName)
+		addNextStep("Provide a *sql.DB in app '%s', then record events in the same transaction as your changes:\n"+
+			"  s.outbox.Add(ctx, tx, \"order.created\", order)", appName)
+	},
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var pprofAllowRemote bool
+
+func init() {
+	generatePprofCmd.Flags().BoolVar(&pprofAllowRemote, "allow-remote", false, "serve the profiling endpoints to non-loopback clients too")
+	generateCmd.AddCommand(generatePprofCmd)
+}
+
+var generatePprofCmd = &cobra.Command{
+	Use:   "pprof [app-name]",
+	Short: "Mount net/http/pprof profiling endpoints on an app, off by default",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating pprof endpoints for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+		data["PprofLocalOnly"] = "true"
+		if pprofAllowRemote {
+			data["PprofLocalOnly"] = ""
+		}
+
+		profilingDir := filepath.Join(projectRoot, "internal", appName, "profiling")
+		createPackageDir(profilingDir)
+		utils.CreateFileFromTmpl(filepath.Join(profilingDir, "profiling.go"), templates.PprofTmpl, data)
+
+		importPath := fmt.Sprintf("%s/internal/%s/profiling", data["ProjectName"], appName)
+		mainPath := appMainPath(projectRoot, appName)
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "profiling.RegisterRoutes("+opsRouter(mainPath)+")"); err != nil {
+			log.Fatalf("Failed to wire pprof: %v", err)
+		}
+
+		log.Printf("Profiling created in %s.", profilingDir)
+		addNextStep("Set %s_PPROF=1 to serve it at /debug/pprof.", data["EnvPrefix"])
+		if pprofAllowRemote {
+			log.Println("Warning: the endpoints accept remote clients; keep them behind authentication or a private network.")
+		}
+	},
+}
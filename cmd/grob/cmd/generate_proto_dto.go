@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var protoFields []string
+
+// protoScalars maps the Go types allowed in proto-dto --fields to protobuf types.
+var protoScalars = map[string]string{
+	"string":    "string",
+	"bool":      "bool",
+	"int":       "int64",
+	"int32":     "int32",
+	"int64":     "int64",
+	"uint":      "uint64",
+	"uint32":    "uint32",
+	"uint64":    "uint64",
+	"float32":   "float",
+	"float64":   "double",
+	"[]byte":    "bytes",
+	"time.Time": "google.protobuf.Timestamp",
+}
+
+func init() {
+	generateProtoDTOCmd.Flags().StringSliceVar(&protoFields, "fields", nil, `message fields as NAME:TYPE with Go types, e.g. "id:int64,email:string,tags:[]string,created_at:time.Time"`)
+	generateCmd.AddCommand(generateProtoDTOCmd)
+}
+
+var generateProtoDTOCmd = &cobra.Command{
+	Use:     "proto-dto [name]",
+	Short:   "Generate a shared protobuf message in pkg/pb",
+	Example: `  grob generate proto-dto user_profile --fields id:int64,email:string,created_at:time.Time`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		log.Printf("Generating protobuf message '%s'", name)
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+		data := utils.TemplateData(projectRoot)
+		data["MessageName"] = utils.GoName(name)
+		if !token.IsIdentifier(data["MessageName"]) {
+			log.Fatalf("Message name %q is not a valid identifier", name)
+		}
+		if err := addProtoFields(data, protoFields); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		pbDir := filepath.Join(projectRoot, "pkg", "pb")
+		if err := os.MkdirAll(pbDir, utils.DirMode); err != nil {
+			log.Fatalf("Failed to create %s: %v", pbDir, err)
+		}
+		fileName := utils.TagName(data["MessageName"], utils.TagSnake) + ".proto"
+		protoPath := filepath.Join(pbDir, fileName)
+		if _, err := os.Stat(protoPath); err == nil {
+			log.Fatalf("%s already exists", protoPath)
+		}
+		utils.CreateFileFromTmpl(protoPath, templates.ProtoDTOTmpl, data)
+
+		pbPath := filepath.Join(pbDir, "pb.go")
+		directive := fmt.Sprintf("//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative %s\n", fileName)
+		if _, err := os.Stat(pbPath); err != nil {
+			utils.CreateFileFromTmpl(pbPath, templates.PbPackageTmpl, data)
+			directive = "\n" + directive
+		}
+		if err := appendToFile(pbPath, directive); err != nil {
+			log.Fatalf("Failed to add the go:generate directive: %v", err)
+		}
+
+		if err := utils.AddRequire(projectRoot, "google.golang.org/protobuf", "v1.34.2"); err != nil {
+			log.Fatalf("Failed to update go.mod: %v", err)
+		}
+
+		log.Printf("Message %s created in %s.", data["MessageName"], protoPath)
+		addNextStep("Run 'go generate ./pkg/pb' to generate its Go type, then import it from %s/pkg/pb.", data["ProjectName"])
+	},
+}
+
+// addProtoFields converts NAME:TYPE pairs with Go types into numbered protobuf
+// field declarations. A []T type becomes a repeated field.
+func addProtoFields(data map[string]string, values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("--fields is required, e.g. --fields id:int64,name:string")
+	}
+
+	var fields []string
+	seen := map[string]bool{}
+	timestamp := false
+	for i, v := range values {
+		name, typ, ok := strings.Cut(v, ":")
+		name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+		if !ok || name == "" || typ == "" {
+			return fmt.Errorf("invalid field %q: use NAME:TYPE", v)
+		}
+		field := utils.TagName(utils.GoName(name), utils.TagSnake)
+		if !token.IsIdentifier(field) {
+			return fmt.Errorf("field name %q is not a valid identifier", name)
+		}
+		if seen[field] {
+			return fmt.Errorf("field %s is declared twice", field)
+		}
+		seen[field] = true
+
+		label := ""
+		if elem, ok := strings.CutPrefix(typ, "[]"); ok && typ != "[]byte" {
+			label, typ = "repeated ", elem
+		}
+		protoType, ok := protoScalars[typ]
+		if !ok {
+			return fmt.Errorf("field %s: unsupported type %q", name, typ)
+		}
+		timestamp = timestamp || typ == "time.Time"
+		fields = append(fields, fmt.Sprintf("  %s%s %s = %d;", label, protoType, field, i+1))
+	}
+
+	data["ProtoImports"] = ""
+	if timestamp {
+		data["ProtoImports"] = `import "google/protobuf/timestamp.proto";`
+	}
+	data["ProtoFields"] = strings.Join(fields, "\n")
+	return nil
+}
+
+// appendToFile appends text to the end of an existing file.
+func appendToFile(path, text string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
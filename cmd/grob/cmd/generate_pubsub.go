@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generatePubSubCmd)
+}
+
+var generatePubSubCmd = &cobra.Command{
+	Use:   "pubsub [app-name] [module-name]",
+	Short: "Generate an in-process event bus for an app's modules",
+	Long: `Generate internal/<app>/pubsub, an in-process event bus with typed topics:
+
+  pubsub.Subscribe(bus, topic, handler)
+  pubsub.Publish(ctx, bus, topic, event)
+
+Handlers run in goroutines of their own with panic recovery, so a slow or
+failing handler never holds up the publisher. PubSubModule provides the *Bus to
+the container and is registered in the app's main.go, where Start subscribes
+the Subscribers that modules add to pubsub.SubscriberGroup and, on shutdown,
+waits for running handlers.
+
+With a module name, the module also gets a sample topic and a Subscriber for
+it, in <module>.subscriber.go. The package's example_test.go shows the bus on
+its own.`,
+	Example: `  grob generate pubsub api
+  grob generate pubsub api users`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating pubsub bus for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Fatalf("App '%s' has no dependency injection container to provide the bus with", appName)
+		}
+
+		var subscriberPath string
+		var moduleDir string
+		if len(args) == 2 {
+			_, data, moduleDir = loadModule(appName, args[1])
+			subscriberPath = filepath.Join(moduleDir, fmt.Sprintf("%s.subscriber.go", data["ModuleName"]))
+			if _, err := os.Stat(subscriberPath); err == nil {
+				log.Fatalf("%s already exists", subscriberPath)
+			}
+		}
+
+		var files createdFiles
+		err := ensurePubSubPackage(projectRoot, data, &files)
+		if err == nil && subscriberPath != "" {
+			err = files.tmpl(subscriberPath, templates.ModuleSubscriberTmpl, data)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/internal/" + appName + "/pubsub"
+		modules, err := utils.ParseAppModules(mainPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", mainPath, err)
+		}
+		registered := false
+		for _, m := range modules {
+			registered = registered || m.ImportPath == importPath
+		}
+		if !registered {
+			if err := utils.AddModuleToAppMain(mainPath, importPath, "pubsub", "PubSub"); err != nil {
+				log.Fatalf("Failed to register PubSubModule: %v", err)
+			}
+		}
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "defer pubsub.Start()()"); err != nil {
+			log.Fatalf("Failed to start the bus in %s: %v", mainPath, err)
+		}
+
+		if subscriberPath == "" {
+			log.Printf("Bus created and PubSubModule registered in app '%s'.", appName)
+			addNextStep("Inject *pubsub.Bus into services to publish, and add subscribers with 'grob generate pubsub %s <module>'.", appName)
+			return
+		}
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", data["ModuleName"]))
+		ctor := "New" + data["ModuleType"] + "Subscriber"
+		ok, err := utils.AddProviderToModule(modulePath, ctor)
+		if err != nil {
+			log.Fatalf("Failed to register %s: %v", ctor, err)
+		}
+		if !ok {
+			addProvideStep(ctor, modulePath)
+		}
+		log.Printf("%sSubscriber created in %s.", data["ModuleType"], subscriberPath)
+		addNextStep("Replace %sChanged and %sChangedTopic with the module's own events, and handle them in %sSubscriber.", data["ModuleType"], data["ModuleType"], data["ModuleType"])
+		addNextStep("Inject *pubsub.Bus into services and publish with pubsub.Publish.")
+	},
+}
+
+// ensurePubSubPackage creates the app's pubsub package unless it already exists.
+func ensurePubSubPackage(projectRoot string, data map[string]string, files *createdFiles) error {
+	dir := filepath.Join(projectRoot, "internal", data["AppName"], "pubsub")
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if err := os.Mkdir(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create pubsub package: %w", err)
+	}
+	for _, f := range [][2]string{
+		{"pubsub.go", templates.PubSubTmpl},
+		{"module.go", templates.PubSubModuleTmpl},
+		{"example_test.go", templates.PubSubExampleTmpl},
+	} {
+		if err := files.tmpl(filepath.Join(dir, f[0]), f[1], data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
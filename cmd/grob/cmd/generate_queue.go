@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var queueBackend string
+
+// queueBackends maps each --backend value to its implementation template and client library.
+var queueBackends = map[string]struct {
+	file, tmpl, module, version string
+}{
+	"redis": {"redis.go", templates.QueueRedisTmpl, "github.com/redis/go-redis/v9", "v9.7.0"},
+	"amqp":  {"amqp.go", templates.QueueAMQPTmpl, "github.com/rabbitmq/amqp091-go", "v1.10.0"},
+}
+
+func init() {
+	generateQueueCmd.Flags().StringVar(&queueBackend, "backend", "redis", "queue backend: redis or amqp")
+	generateCmd.AddCommand(generateQueueCmd)
+}
+
+var generateQueueCmd = &cobra.Command{
+	Use:   "queue [app-name]",
+	Short: "Generate a job queue with a publisher and consumer, and provide it to an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating %s job queue for app '%s'", queueBackend, appName)
+
+		backend, ok := queueBackends[queueBackend]
+		if !ok {
+			log.Fatalf("Unknown queue backend %q: use redis or amqp", queueBackend)
+		}
+
+		projectRoot, data := loadApp(appName)
+		data["QueueBackend"] = queueBackend
+
+		dir := filepath.Join(projectRoot, "pkg", "queue")
+		if _, err := os.Stat(dir); err == nil {
+			log.Printf("%s already exists; reusing it.", dir)
+		} else {
+			if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+				log.Fatalf("Failed to create queue package: %v", err)
+			}
+			utils.CreateFileFromTmpl(filepath.Join(dir, "queue.go"), templates.QueueTmpl, data)
+			utils.CreateFileFromTmpl(filepath.Join(dir, backend.file), backend.tmpl, data)
+			utils.CreateFileFromTmpl(filepath.Join(dir, "module.go"), templates.QueueModuleTmpl, data)
+			if err := utils.AddRequire(projectRoot, backend.module, backend.version); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		// Worker apps have no dependency injection container; they use the queue directly.
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Println("Worker app detected; the queue is not registered as a module.")
+			addNextStep("Consume jobs in Run with, for example:\n" +
+				"  q := queue.NewRedisQueue(client, \"jobs\") // or queue.NewAMQPQueue(conn, \"jobs\")\n" +
+				"  q.Consume(ctx, queue.Dispatcher{\"email.send\": sendEmail}.Handle)")
+			return
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/pkg/queue"
+		modules, err := utils.ParseAppModules(mainPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", mainPath, err)
+		}
+		for _, m := range modules {
+			if m.ImportPath == importPath {
+				log.Printf("QueueModule is already registered in app '%s'.", appName)
+				return
+			}
+		}
+		if err := utils.AddModuleToAppMain(mainPath, importPath, "queue", "Queue"); err != nil {
+			log.Fatalf("Failed to register QueueModule: %v", err)
+		}
+
+		log.Printf("Queue created in %s and QueueModule registered in app '%s'.", dir, appName)
+		addNextStep("Inject queue.Publisher into services and enqueue work with queue.NewJob.")
+	},
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	rateStrategy string
+	rateRPS      int
+	rateBurst    int
+	rateKey      string
+	rateStore    string
+)
+
+func init() {
+	generateRateLimitCmd.Flags().StringVar(&rateStrategy, "strategy", "token-bucket", "limiting strategy: token-bucket or fixed-window")
+	generateRateLimitCmd.Flags().IntVar(&rateRPS, "rps", 10, "requests per second allowed per client")
+	generateRateLimitCmd.Flags().IntVar(&rateBurst, "burst", 0, "requests a client may make at once with token-bucket (default 2x --rps)")
+	generateRateLimitCmd.Flags().StringVar(&rateKey, "key", "ip", `what requests are counted against: "ip" or "header:NAME", e.g. header:X-API-Key`)
+	generateRateLimitCmd.Flags().StringVar(&rateStore, "store", "memory", "limiter state: memory (per instance) or redis (shared)")
+	generateCmd.AddCommand(generateRateLimitCmd)
+}
+
+var generateRateLimitCmd = &cobra.Command{
+	Use:   "ratelimit [app-name]",
+	Short: "Generate a rate-limiting middleware for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating %s rate limiter for app '%s'", rateStrategy, appName)
+
+		if rateStrategy != "token-bucket" && rateStrategy != "fixed-window" {
+			log.Fatalf("Unknown strategy %q: use token-bucket or fixed-window", rateStrategy)
+		}
+		if rateStore != "memory" && rateStore != "redis" {
+			log.Fatalf("Unknown store %q: use memory or redis", rateStore)
+		}
+		if rateRPS <= 0 {
+			log.Fatal("--rps must be positive")
+		}
+		if rateBurst <= 0 {
+			rateBurst = 2 * rateRPS
+		}
+		keyFunc, err := rateKeyFunc(rateKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		projectRoot, data := loadApp(appName)
+		data["RateStrategy"] = rateStrategy
+		data["RateRPS"] = strconv.Itoa(rateRPS)
+		data["RateBurst"] = strconv.Itoa(rateBurst)
+		data["RateKeyFunc"] = keyFunc
+
+		dir := filepath.Join(projectRoot, "internal", appName, "ratelimit")
+		createPackageDir(dir)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "ratelimit.go"), templates.RateLimitTmpl, data)
+		if rateStore == "redis" {
+			utils.CreateFileFromTmpl(filepath.Join(dir, "redis.go"), templates.RateLimitRedisTmpl, data)
+			if err := utils.AddRequire(projectRoot, "github.com/redis/go-redis/v9", "v9.7.0"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		} else {
+			utils.CreateFileFromTmpl(filepath.Join(dir, "memory.go"), templates.RateLimitMemoryTmpl, data)
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/ratelimit", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "", importPath, "app.Router().Use(ratelimit.Default())"); err != nil {
+			log.Fatalf("Failed to register rate limit middleware: %v", err)
+		}
+
+		log.Printf("Rate limiter created in %s and registered on every route.", dir)
+	},
+}
+
+// rateKeyFunc returns the KeyFunc expression for a --key value.
+func rateKeyFunc(key string) (string, error) {
+	if key == "ip" {
+		return "ByIP", nil
+	}
+	if name, ok := strings.CutPrefix(key, "header:"); ok && name != "" {
+		return fmt.Sprintf("ByHeader(%q)", name), nil
+	}
+	return "", fmt.Errorf("invalid --key %q: use ip or header:NAME", key)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	accessLogger    string
+	accessLogLevel  string
+	accessBodyLimit int
+)
+
+func init() {
+	generateRequestLoggingCmd.Flags().StringVar(&accessLogger, "logger", "slog", "logging library: slog (standard library, Go 1.21+) or zap")
+	generateRequestLoggingCmd.Flags().StringVar(&accessLogLevel, "level", "info", "default lowest level logged: debug, info, warn, or error")
+	generateRequestLoggingCmd.Flags().IntVar(&accessBodyLimit, "body-limit", 0, "default number of request body bytes logged; 0 logs no bodies")
+	generateCmd.AddCommand(generateRequestLoggingCmd)
+}
+
+var generateRequestLoggingCmd = &cobra.Command{
+	Use:   "request-logging [app-name]",
+	Short: "Generate a structured JSON access-log middleware for an app",
+	Long: `Generate internal/<app>/accesslog, a gin middleware logging every request as
+one JSON line on stdout with slog or zap: method, path, route, status, latency,
+request ID, response bytes, and client IP. Requests are logged at info, client
+errors at warn, and server errors at error.
+
+At runtime the middleware reads:
+
+  <APP>_ACCESS_LOG_LEVEL       lowest level logged (default --level)
+  <APP>_ACCESS_LOG_HEADERS     true to log request headers; Authorization,
+                               Cookie, API key, and similar headers are redacted
+  <APP>_ACCESS_LOG_REDACT      more headers to redact, comma-separated
+  <APP>_ACCESS_LOG_BODY_LIMIT  bytes of request bodies to log (default
+                               --body-limit); bodies are not logged unless set
+
+The middleware is added to the app's router in its main.go.`,
+	Example: `  grob generate request-logging api
+  grob generate request-logging api --logger zap --level warn --body-limit 2048`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating request logging for app '%s'", appName)
+
+		switch accessLogger {
+		case "slog", "zap":
+		default:
+			log.Fatalf("Unknown logger %q: use slog or zap", accessLogger)
+		}
+		switch accessLogLevel {
+		case "debug", "info", "warn", "error":
+		default:
+			log.Fatalf("Unknown level %q: use debug, info, warn, or error", accessLogLevel)
+		}
+		if accessBodyLimit < 0 {
+			log.Fatal("--body-limit must not be negative")
+		}
+
+		projectRoot, data := loadApp(appName)
+		if accessLogger == "slog" {
+			goVersion, err := utils.GoVersion(projectRoot)
+			if err != nil {
+				log.Fatalf("Failed to read go.mod: %v", err)
+			}
+			if semver.Compare("v"+goVersion, "v1.21") < 0 {
+				log.Fatalf("log/slog needs Go 1.21, but go.mod has go %s: raise it with 'go mod edit -go=1.21', or use --logger zap", goVersion)
+			}
+		}
+
+		dir := filepath.Join(projectRoot, "internal", appName, "accesslog")
+		createPackageDir(dir)
+		data["AccessLogger"] = accessLogger
+		data["AccessLogLevel"] = accessLogLevel
+		data["AccessLogBodyLimit"] = fmt.Sprint(accessBodyLimit)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "accesslog.go"), templates.AccessLogTmpl, data)
+
+		if accessLogger == "zap" {
+			if err := utils.AddRequire(projectRoot, "go.uber.org/zap", "v1.27.0"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/accesslog", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "", importPath, "app.Router().Use(accesslog.Middleware())"); err != nil {
+			log.Fatalf("Failed to register the access log middleware: %v", err)
+		}
+
+		log.Printf("Access log middleware created in %s and registered.", dir)
+		if accessLogger == "zap" {
+			addNextStep("Run 'go mod tidy' to download zap.")
+		}
+		addNextStep("Remove gin.Logger() from the app's router, if it has one, so requests are not logged twice.")
+	},
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	respCacheStore string
+	respCacheTTL   time.Duration
+)
+
+func init() {
+	generateRespCacheCmd.Flags().StringVar(&respCacheStore, "store", "memory", "cache backend: memory or redis")
+	generateRespCacheCmd.Flags().DurationVar(&respCacheTTL, "ttl", 30*time.Second, "how long responses are cached")
+	generateCmd.AddCommand(generateRespCacheCmd)
+}
+
+var generateRespCacheCmd = &cobra.Command{
+	Use:   "response-cache [app-name]",
+	Short: "Generate a response-caching middleware for an app's GET endpoints",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating response cache for app '%s'", appName)
+
+		if respCacheStore != "memory" && respCacheStore != "redis" {
+			log.Fatalf("Unknown cache store %q: use memory or redis", respCacheStore)
+		}
+		if respCacheTTL <= 0 {
+			log.Fatal("--ttl must be positive")
+		}
+
+		projectRoot, data := loadApp(appName)
+		data["CacheStore"] = respCacheStore
+		data["CacheTTL"] = durationExpr(respCacheTTL)
+
+		dir := filepath.Join(projectRoot, "internal", appName, "respcache")
+		createPackageDir(dir)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "respcache.go"), templates.ResponseCacheTmpl, data)
+		utils.CreateFileFromTmpl(filepath.Join(dir, "store.go"), templates.ResponseCacheStoreTmpl, data)
+		if respCacheStore == "redis" {
+			if err := utils.AddRequire(projectRoot, "github.com/redis/go-redis/v9", "v9.7.0"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/respcache", data["ProjectName"], appName)
+		if err := utils.AddMiddlewareToAppMain(appMainPath(projectRoot, appName), "", importPath, "app.Router().Use(respcache.Middleware(respcache.NewStore(), respcache.DefaultTTL))"); err != nil {
+			log.Fatalf("Failed to register response cache middleware: %v", err)
+		}
+
+		log.Printf("Response cache created in %s and registered for every GET route.", dir)
+		log.Println("Send the X-Cache-Bypass header to skip the cache while debugging.")
+	},
+}
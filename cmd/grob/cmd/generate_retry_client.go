@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	retryMaxRetries  int
+	retryBaseBackoff time.Duration
+	retryMaxBackoff  time.Duration
+	retryTimeout     time.Duration
+)
+
+func init() {
+	generateRetryClientCmd.Flags().IntVar(&retryMaxRetries, "max-retries", 3, "how many times a request is retried after the first attempt")
+	generateRetryClientCmd.Flags().DurationVar(&retryBaseBackoff, "base-backoff", 200*time.Millisecond, "wait before the first retry; it doubles on each retry, plus jitter")
+	generateRetryClientCmd.Flags().DurationVar(&retryMaxBackoff, "max-backoff", 30*time.Second, "longest wait between retries; a longer Retry-After is not waited for")
+	generateRetryClientCmd.Flags().DurationVar(&retryTimeout, "timeout", 30*time.Second, "time limit of a whole call, including retries")
+	generateCmd.AddCommand(generateRetryClientCmd)
+}
+
+var generateRetryClientCmd = &cobra.Command{
+	Use:   "retry-client [app-name]",
+	Short: "Generate an HTTP client that retries transient failures with backoff, provided to an app",
+	Long: `Generate internal/<app>/retryclient, an HTTP client for calling external APIs.
+
+Its Transport retries network errors and 429, 502, 503, and 504 responses with
+exponential backoff and jitter, waits as long as a Retry-After header asks, and
+stops as soon as the request's context is done. Only requests that are safe to
+repeat are retried: GET, HEAD, OPTIONS, PUT, DELETE, and requests carrying an
+Idempotency-Key header.
+
+RetryClientModule is registered in the app and provides *retryclient.Client;
+the Transport can also wrap the http.Client of a third-party SDK. Modules
+created with 'grob generate module-client --retry-client' use it.`,
+	Example: `  grob generate retry-client api
+  grob generate retry-client api --max-retries 5 --timeout 1m`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating retry client for app '%s'", appName)
+
+		if retryMaxRetries < 0 {
+			log.Fatalf("Invalid --max-retries %d: it cannot be negative", retryMaxRetries)
+		}
+		if retryBaseBackoff <= 0 || retryMaxBackoff < retryBaseBackoff {
+			log.Fatal("Invalid backoff: --base-backoff must be positive and no longer than --max-backoff")
+		}
+
+		projectRoot, data := loadApp(appName)
+		dir := filepath.Join(projectRoot, "internal", appName, "retryclient")
+		if _, err := os.Stat(dir); err == nil {
+			log.Fatalf("%s already exists", dir)
+		}
+
+		var files createdFiles
+		err := ensureRetryClient(projectRoot, data, &files)
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Retry client created in %s and RetryClientModule registered in app '%s'.", dir, appName)
+		addNextStep("Inject *retryclient.Client into services that call external APIs, or set retryclient.Transport on an existing http.Client.")
+		addNextStep("Send POSTs with an Idempotency-Key header to have them retried.")
+	},
+}
+
+// ensureRetryClient creates the app's retryclient package and registers
+// RetryClientModule, unless the package already exists.
+func ensureRetryClient(projectRoot string, data map[string]string, files *createdFiles) error {
+	appName := data["AppName"]
+	dir := filepath.Join(projectRoot, "internal", appName, "retryclient")
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create retryclient package: %w", err)
+	}
+
+	data["RetryMaxRetries"] = strconv.Itoa(retryMaxRetries)
+	data["RetryBaseBackoff"] = durationExpr(retryBaseBackoff)
+	data["RetryMaxBackoff"] = durationExpr(retryMaxBackoff)
+	data["RetryTimeout"] = durationExpr(retryTimeout)
+	if err := files.tmpl(filepath.Join(dir, "retryclient.go"), templates.RetryClientTmpl, data); err != nil {
+		return err
+	}
+	if err := files.tmpl(filepath.Join(dir, "module.go"), templates.RetryClientModuleTmpl, data); err != nil {
+		return err
+	}
+
+	importPath := fmt.Sprintf("%s/internal/%s/retryclient", data["ProjectName"], appName)
+	if err := utils.AddModuleToAppMain(appMainPath(projectRoot, appName), importPath, "retryclient", "RetryClient"); err != nil {
+		return fmt.Errorf("failed to register RetryClientModule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	sagaSteps           []string
+	sagaDriver          string
+	sagaTable           string
+	sagaStaleAfter      time.Duration
+	sagaRecoverInterval time.Duration
+)
+
+// sagaName matches saga and step names: lower-case words joined by hyphens
+// or underscores, as they are saved in the saga table.
+var sagaName = regexp.MustCompile(`^[a-z][a-z0-9]*([_-][a-z0-9]+)*$`)
+
+func init() {
+	generateSagaCmd.Flags().StringSliceVar(&sagaSteps, "steps", []string{"first", "second"}, "names of the saga's steps, in the order they run")
+	generateSagaCmd.Flags().StringVar(&sagaDriver, "driver", "postgres", "SQL dialect of the saga table: postgres or mysql")
+	generateSagaCmd.Flags().StringVar(&sagaTable, "table", "sagas", "database table saga state is kept in")
+	generateSagaCmd.Flags().DurationVar(&sagaStaleAfter, "stale-after", 5*time.Minute, "default time a saga may go without progress before it is rolled back as interrupted")
+	generateSagaCmd.Flags().DurationVar(&sagaRecoverInterval, "recover-interval", time.Minute, "default interval at which the app looks for interrupted sagas")
+	generateCmd.AddCommand(generateSagaCmd)
+}
+
+var generateSagaCmd = &cobra.Command{
+	Use:   "saga [app-name] [saga-name]",
+	Short: "Generate an orchestrated saga: steps with compensations, run by a coordinator that keeps its state in the database",
+	Long: `Generate a saga, a workflow of steps across services that is rolled back by
+compensating the steps already done when one fails:
+
+  pkg/saga                       the coordinator and its SQL store
+  migrations/<ts>_create_<table> saga table migration (up and down)
+  internal/<app>/sagas           SagasModule, and <saga>.go with the saga's
+                                 data, steps, and constructor
+
+The coordinator runs the steps in order, saving the saga's state after each.
+If a step fails, it compensates that step, which may have partly taken effect,
+and the steps before it, last first, retrying each compensation; a saga whose
+compensation keeps failing is marked failed for an operator, without undoing
+the steps before it out of order.
+
+A saga interrupted by a crash or shutdown is rolled back by the app once it
+has made no progress for <APP>_SAGA_STALE_AFTER (default --stale-after); the
+coordinator renews a saga while its steps run, and versioned updates make sure
+only one process takes a saga over.
+
+pkg/saga and the migration are created with the first saga and reused after.`,
+	Example: `  grob generate saga orders checkout --steps reserve-stock,charge-payment,create-shipment
+  grob generate saga orders refund --driver mysql`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, name := args[0], args[1]
+		log.Printf("Generating saga '%s' for app '%s'", name, appName)
+
+		if !sagaName.MatchString(name) {
+			log.Fatalf("Invalid saga name %q: use lower-case words such as checkout or order-refund", name)
+		}
+		steps, err := parseSagaSteps(sagaSteps)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if _, ok := sqlDriverNames[sagaDriver]; !ok {
+			log.Fatalf("Unknown driver %q: use postgres or mysql", sagaDriver)
+		}
+		if !sqlIdentifier.MatchString(sagaTable) {
+			log.Fatalf("Invalid table name %q", sagaTable)
+		}
+		if sagaStaleAfter < 3*time.Second {
+			log.Fatal("--stale-after must be at least 3s")
+		}
+		if sagaRecoverInterval <= 0 {
+			log.Fatal("--recover-interval must be positive")
+		}
+
+		projectRoot, data := loadApp(appName)
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Fatalf("App '%s' has no dependency injection container to provide the saga with", appName)
+		}
+		sagasDir := filepath.Join(projectRoot, "internal", appName, "sagas")
+		sagaPath := filepath.Join(sagasDir, strings.ReplaceAll(name, "-", "_")+".go")
+		if _, err := os.Stat(sagaPath); err == nil {
+			log.Fatalf("%s already exists", sagaPath)
+		}
+
+		words := utils.SplitWords(name)
+		data["SagaName"] = name
+		data["SagaType"] = utils.GoName(name)
+		data["SagaTable"] = sagaTable
+		data["SagaDriver"] = sagaDriver
+		data["SagaMigration"] = fmt.Sprintf("%s_create_%s", time.Now().UTC().Format("20060102150405"), sagaTable)
+		data["SagaStaleAfter"] = durationExpr(sagaStaleAfter)
+		data["SagaStaleAfterText"] = sagaStaleAfter.String()
+		data["SagaRecover"] = durationExpr(sagaRecoverInterval)
+		data["SagaRecoverText"] = sagaRecoverInterval.String()
+		data["SagaStepNames"] = describeSteps(steps)
+
+		var values []string
+		var code strings.Builder
+		for _, step := range steps {
+			data["StepName"] = step
+			data["StepType"] = strings.ToLower(words[0]) + utils.GoName(strings.Join(words[1:], "_")) + utils.GoName(step) + "Step"
+			values = append(values, fmt.Sprintf("\t\t&%s{},", data["StepType"]))
+			out, err := utils.RenderString(templates.SagaStepTmpl, data)
+			if err != nil {
+				log.Fatalf("Failed to render step %s: %v", step, err)
+			}
+			code.WriteString(out)
+		}
+		data["SagaStepValues"] = strings.Join(values, "\n")
+		data["SagaSteps"] = code.String()
+
+		var files createdFiles
+		pkgDir := filepath.Join(projectRoot, "pkg", "saga")
+		newPkg := false
+		if _, err := os.Stat(pkgDir); err == nil {
+			log.Printf("%s already exists; reusing it.", pkgDir)
+		} else {
+			newPkg = true
+			err = os.MkdirAll(pkgDir, utils.DirMode)
+			if err == nil {
+				err = files.tmpl(filepath.Join(pkgDir, "saga.go"), templates.SagaTmpl, data)
+			}
+			if err == nil {
+				err = files.tmpl(filepath.Join(pkgDir, "store.go"), templates.SagaStoreTmpl, data)
+			}
+			migrationsDir := filepath.Join(projectRoot, "migrations")
+			if err == nil {
+				err = os.MkdirAll(migrationsDir, utils.DirMode)
+			}
+			if err == nil {
+				err = files.tmpl(filepath.Join(migrationsDir, data["SagaMigration"]+".up.sql"), templates.SagaMigrationUpTmpl, data)
+			}
+			if err == nil {
+				err = files.tmpl(filepath.Join(migrationsDir, data["SagaMigration"]+".down.sql"), templates.SagaMigrationDownTmpl, data)
+			}
+		}
+
+		newModule := false
+		modulePath := filepath.Join(sagasDir, "sagas.go")
+		if _, statErr := os.Stat(modulePath); err == nil && statErr != nil {
+			newModule = true
+			err = os.MkdirAll(sagasDir, utils.DirMode)
+			if err == nil {
+				err = files.tmpl(modulePath, templates.SagasModuleTmpl, data)
+			}
+		}
+		if err == nil {
+			err = files.tmpl(sagaPath, templates.SagaDefinitionTmpl, data)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ctor := "New" + data["SagaType"] + "Saga"
+		ok, err := utils.AddProviderToModule(modulePath, ctor)
+		if err != nil {
+			log.Fatalf("Failed to register %s: %v", ctor, err)
+		}
+		if !ok {
+			addProvideStep(ctor, modulePath)
+		}
+
+		if newModule {
+			mainPath := appMainPath(projectRoot, appName)
+			importPath := data["ProjectName"] + "/internal/" + appName + "/sagas"
+			if err := utils.AddModuleToAppMain(mainPath, importPath, "sagas", "Sagas"); err != nil {
+				log.Fatalf("Failed to register SagasModule: %v", err)
+			}
+			if err := utils.AddStatementToAppMain(mainPath, "", importPath, "defer sagas.Start()()"); err != nil {
+				log.Fatalf("Failed to start saga recovery in %s: %v", mainPath, err)
+			}
+		}
+
+		log.Printf("Saga '%s' created in %s with the steps %s.", name, sagaPath, data["SagaStepNames"])
+		if newPkg {
+			addNextStep("Apply the migration in migrations/.")
+		}
+		if newModule {
+			addNextStep("Provide a *sql.DB in app '%s' (see 'grob generate graceful-db'); the sagas keep their state in its database.", appName)
+			if sagaDriver == "mysql" {
+				addNextStep("Add parseTime=true to the MySQL DSN so the saga table's timestamps scan into time.Time.")
+			}
+		}
+		addNextStep("Fill in %sData and the steps' Execute and Compensate in %s, then inject *sagas.%sSaga and call Run.", data["SagaType"], sagaPath, data["SagaType"])
+	},
+}
+
+// parseSagaSteps validates the --steps names.
+func parseSagaSteps(values []string) ([]string, error) {
+	var steps []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if !sagaName.MatchString(v) {
+			return nil, fmt.Errorf("invalid step name %q: use lower-case words such as reserve-stock", v)
+		}
+		if seen[v] {
+			return nil, fmt.Errorf("step %s is listed twice in --steps", v)
+		}
+		seen[v] = true
+		steps = append(steps, v)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("--steps needs at least one step")
+	}
+	return steps, nil
+}
+
+// describeSteps lists the steps for doc comments, e.g. "reserve-stock,
+// charge-payment, and create-shipment".
+func describeSteps(steps []string) string {
+	last := len(steps) - 1
+	switch last {
+	case 0:
+		return steps[0]
+	case 1:
+		return steps[0] + " and " + steps[1]
+	}
+	return strings.Join(steps[:last], ", ") + ", and " + steps[last]
+}
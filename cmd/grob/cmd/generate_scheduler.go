@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	generateSchedulerCmd.Flags().DurationVar(&appInterval, "interval", time.Minute, "run interval of the example job")
+	generateCmd.AddCommand(generateSchedulerCmd)
+}
+
+var generateSchedulerCmd = &cobra.Command{
+	Use:   "scheduler [app-name]",
+	Short: "Generate a scheduler app that runs the project's periodic jobs on cron schedules",
+	Long: `Generate a scheduler app, one place for the project's periodic tasks. Jobs
+register themselves in its registry, each from an init function in its own
+file of the app, with a name, a cron schedule, and a Run function; an example
+job is included. Schedules can be overridden with <APP>_<JOB>_SCHEDULE.
+
+The scheduler is registered as an AppRunner, so it starts and shuts down
+together with the project's other apps: on shutdown it starts no new runs,
+cancels the context of the running ones, and waits for them to finish. A job
+whose previous run is still going is skipped, and a panicking job is logged
+without stopping the others. Schedules are parsed by robfig/cron.
+
+This is a shorthand for 'grob create-app [app-name] --type scheduler'.`,
+	Example: `  grob generate scheduler ops --interval 5m`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Creating new scheduler: %s", appName)
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		appType = "scheduler"
+		files, err := createApp(projectRoot, appName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportCreated(projectRoot, files)
+	},
+}
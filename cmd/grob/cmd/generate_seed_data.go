@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	seedModel string
+	seedCount int
+)
+
+func init() {
+	generateSeedDataCmd.Flags().StringVar(&seedModel, "model", "", "model whose table is seeded (default: the only model in the module)")
+	generateSeedDataCmd.Flags().IntVar(&seedCount, "count", 50, "default number of rows the seeder inserts; its -count flag overrides it")
+	generateCmd.AddCommand(generateSeedDataCmd)
+}
+
+var generateSeedDataCmd = &cobra.Command{
+	Use:   "seed-data [app-name] [module-name]",
+	Short: "Generate a seeder that fills a module's table with fake rows for development",
+	Long: `Generate a seeder for a module's model, inserting rows with fake values through
+the repository from 'grob generate sql-repository':
+
+  <module>/<model>.seed.go       Fake<Model> and Seed<Model>Rows
+  <module>/seed/<model>/main.go  program running Seed<Model>Rows against DATABASE_URL
+
+Values are made with gofakeit, chosen by each field's type and name: an Email
+field gets email addresses, a FirstName field first names, a Price field
+prices, and so on. Fields it cannot fake, such as pointers and structs, are
+left at their zero value.
+
+The seeder inserts --count rows unless run with -count; -seed makes it insert
+the same rows on every run.`,
+	Example: `  grob generate seed-data users profile --count 100
+  go run ./internal/users/profile/seed/profile -count 1000`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating seed data for module '%s' in app '%s'", moduleName, appName)
+
+		if seedCount < 1 {
+			log.Fatal("--count must be at least 1")
+		}
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		model, err := findModel(moduleDir, seedModel)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		lower := strings.ToLower(model.Name)
+		repoPath := filepath.Join(moduleDir, fmt.Sprintf("%s.repository.go", lower))
+		if _, err := os.Stat(repoPath); err != nil {
+			log.Fatalf("%s has no SQL repository; run 'grob generate sql-repository %s %s --model %s' first.", model.Name, appName, moduleName, model.Name)
+		}
+		repo, err := utils.ParseSQLRepository(repoPath, model.Name)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", repoPath, err)
+		}
+
+		seedPath := filepath.Join(moduleDir, fmt.Sprintf("%s.seed.go", lower))
+		mainPath := filepath.Join(moduleDir, "seed", lower, "main.go")
+		for _, p := range []string{seedPath, mainPath} {
+			if _, err := os.Stat(p); err == nil {
+				log.Fatalf("%s already exists", p)
+			}
+		}
+
+		fields, skipped, usesTime := seedFields(model, repo)
+		data["ModelName"] = model.Name
+		data["RepoTable"] = utils.UnquoteTable(repo.Table)
+		data["RepoDriver"] = "postgres"
+		if repo.Placeholder == "?" {
+			data["RepoDriver"] = "mysql"
+		}
+		data["SeedDriverName"] = sqlDriverNames[data["RepoDriver"]]
+		data["SeedFields"] = fields
+		data["SeedSkipped"] = strings.Join(skipped, ", ")
+		data["SeedTime"] = ""
+		if usesTime {
+			data["SeedTime"] = "true"
+		}
+		data["SeedCount"] = fmt.Sprint(seedCount)
+		relMain, err := filepath.Rel(projectRoot, filepath.Dir(mainPath))
+		if err != nil {
+			log.Fatal(err)
+		}
+		data["SeedPath"] = filepath.ToSlash(relMain)
+		data["SeedCommand"] = path.Base(data["SeedPath"])
+		data["ModuleImportPath"] = utils.ModuleImportPath(data["ProjectName"], appName, data["ModuleName"], data["DirStyle"])
+		data["ModuleImportName"] = utils.ModuleImportName(data["ModuleName"])
+
+		var files createdFiles
+		err = files.tmpl(seedPath, templates.SeedTmpl, data)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(mainPath), utils.DirMode)
+		}
+		if err == nil {
+			err = files.tmpl(mainPath, templates.SeedMainTmpl, data)
+		}
+		for _, req := range [][2]string{{"github.com/brianvoe/gofakeit/v6", "v6.28.0"}, sqlDriverModules[data["RepoDriver"]]} {
+			if err == nil {
+				err = utils.AddRequire(projectRoot, req[0], req[1])
+			}
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Seeder for table %s created in %s.", data["RepoTable"], mainPath)
+		if len(skipped) > 0 {
+			addNextStep("Set %s in Fake%s if their columns are NOT NULL without a default.", strings.Join(skipped, ", "), model.Name)
+		}
+		addNextStep("Run 'go mod tidy' to fetch gofakeit and the %s driver.", data["RepoDriver"])
+		addNextStep("Seed a development database with 'DATABASE_URL=... go run ./%s'.", data["SeedPath"])
+	},
+}
+
+// seedFields returns the fields of the Fake<Model> composite literal, one per
+// line, the fields it has no fake value for, and whether the values use now.
+// Integer keys are left to the database and the soft-delete column to NULL.
+func seedFields(model utils.Model, repo utils.SQLRepository) (string, []string, bool) {
+	var lines, skipped []string
+	usesTime := false
+	for _, f := range model.Fields {
+		key := f.Column == repo.IDColumn
+		if f.Name == utils.SoftDeleteField || (key && integerTypes[strings.TrimPrefix(f.Type, "*")]) {
+			continue
+		}
+		value, ok := fakeValue(f, key)
+		if !ok {
+			skipped = append(skipped, f.Name)
+			continue
+		}
+		usesTime = usesTime || f.Type == "time.Time"
+		lines = append(lines, fmt.Sprintf("\t\t%s: %s,", f.Name, value))
+	}
+	return strings.Join(lines, "\n"), skipped, usesTime
+}
+
+// fakeValue returns the gofakeit expression for a field, chosen by its type
+// and then by the words of its name.
+func fakeValue(f utils.ModelField, key bool) (string, bool) {
+	words := utils.SplitWords(f.Name)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	name := strings.Join(words, "")
+	contains := func(parts ...string) bool {
+		for _, p := range parts {
+			if strings.Contains(name, p) {
+				return true
+			}
+		}
+		return false
+	}
+	word := func(ws ...string) bool {
+		for _, w := range ws {
+			for _, fw := range words {
+				if fw == w {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch t := f.Type; {
+	case t == "string":
+		switch {
+		case key:
+			return "f.UUID()", true
+		case contains("email"):
+			return "f.Email()", true
+		case contains("firstname", "givenname"):
+			return "f.FirstName()", true
+		case contains("lastname", "surname", "familyname"):
+			return "f.LastName()", true
+		case contains("username", "nickname") || word("login", "handle"):
+			return "f.Username()", true
+		case contains("password"):
+			return "f.Password(true, true, true, false, false, 16)", true
+		case contains("phone", "mobile"):
+			return "f.Phone()", true
+		case contains("url", "website", "avatar") || word("link", "homepage"):
+			return "f.URL()", true
+		case contains("company", "organization", "organisation"):
+			return "f.Company()", true
+		case word("ip"):
+			return "f.IPv4Address()", true
+		case contains("street", "address"):
+			return "f.Street()", true
+		case contains("city"):
+			return "f.City()", true
+		case contains("country"):
+			return "f.Country()", true
+		case word("state", "province", "region"):
+			return "f.State()", true
+		case contains("zip", "postal", "postcode"):
+			return "f.Zip()", true
+		case contains("currency"):
+			return "f.CurrencyShort()", true
+		case contains("color", "colour"):
+			return "f.Color()", true
+		case contains("description", "summary", "content", "comment") || word("bio", "body", "note", "notes", "text"):
+			return `f.Paragraph(1, 3, 12, " ")`, true
+		case word("title", "subject", "headline"):
+			return "f.Sentence(4)", true
+		case word("name"):
+			return "f.Name()", true
+		}
+		return "f.Word()", true
+	case t == "bool":
+		return "f.Bool()", true
+	case integerTypes[t], t == "int8", t == "int16", t == "uint8", t == "uint16":
+		expr := "f.Number(1, 1000)"
+		switch {
+		case word("age"):
+			expr = "f.Number(18, 90)"
+		case word("year"):
+			expr = "f.Year()"
+		case contains("count", "quantity", "stock") || word("qty"):
+			expr = "f.Number(0, 100)"
+		case t == "int8", t == "uint8":
+			expr = "f.Number(1, 100)"
+		}
+		if t != "int" {
+			expr = t + "(" + expr + ")"
+		}
+		return expr, true
+	case t == "float32", t == "float64":
+		expr := "f.Float64Range(0, 1000)"
+		switch {
+		case contains("price", "amount", "cost", "total", "balance"):
+			expr = "f.Price(1, 1000)"
+		case word("lat", "latitude"):
+			expr = "f.Latitude()"
+		case word("lng", "lon", "long", "longitude"):
+			expr = "f.Longitude()"
+		case contains("rating", "score"):
+			expr = "f.Float64Range(0, 5)"
+		}
+		if t == "float32" {
+			expr = "float32(" + expr + ")"
+		}
+		return expr, true
+	case t == "time.Time":
+		if contains("birth") || word("dob") {
+			return "f.DateRange(now.AddDate(-80, 0, 0), now.AddDate(-18, 0, 0))", true
+		}
+		return "f.DateRange(now.AddDate(-1, 0, 0), now)", true
+	}
+	return "", false
+}
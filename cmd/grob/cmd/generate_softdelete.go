@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var softDeleteModel string
+
+func init() {
+	generateSoftDeleteCmd.Flags().StringVar(&softDeleteModel, "model", "", "model to soft-delete (default: the only model in the module)")
+	generateCmd.AddCommand(generateSoftDeleteCmd)
+}
+
+var generateSoftDeleteCmd = &cobra.Command{
+	Use:   "softdelete [app-name] [module-name]",
+	Short: "Add a DeletedAt field to a module's model and soft deletes to its SQL repository",
+	Long: `Add a DeletedAt *time.Time field to a module's model. If the module has a
+repository from 'grob generate sql-repository', its SELECT and UPDATE queries
+get a "deleted_at IS NULL" condition and it gains a SoftDelete method, which
+sets deleted_at instead of removing the row. Delete still removes rows.
+
+Running it again on a model that already has soft deletes changes nothing.`,
+	Example: `  grob generate softdelete users profile`,
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Adding soft delete to module '%s' in app '%s'", moduleName, appName)
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		model, err := findModel(moduleDir, softDeleteModel)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		addedField, err := utils.AddSoftDeleteField(model.Path, model.Name, data["StructTags"])
+		if err != nil {
+			log.Fatalf("Failed to add %s to %s: %v", utils.SoftDeleteField, model.Name, err)
+		}
+		if addedField {
+			log.Printf("Added %s to %s in %s.", utils.SoftDeleteField, model.Name, model.Path)
+			// Re-read the model for the column of the new field.
+			if model, err = findModel(moduleDir, model.Name); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+		}
+		column, _ := softDeleteColumn(model)
+
+		repoPath := filepath.Join(moduleDir, fmt.Sprintf("%s.repository.go", strings.ToLower(model.Name)))
+		if _, err := os.Stat(repoPath); err != nil {
+			if !addedField {
+				log.Printf("%s already has soft delete.", model.Name)
+				return
+			}
+			addNextStep("Run 'grob generate sql-repository %s %s --model %s'; the repository will skip soft-deleted rows.", appName, moduleName, model.Name)
+			addNextStep("Add a nullable %s column to the model's table, e.g. ALTER TABLE <table> ADD COLUMN %s TIMESTAMP NULL;", column, column)
+			return
+		}
+
+		repo, err := addSoftDeleteMethod(repoPath, model.Name, column)
+		if err != nil {
+			log.Fatalf("Failed to add soft delete to %s: %v", repoPath, err)
+		}
+		if repo.HasSoftDelete {
+			if !addedField {
+				log.Printf("%s already has soft delete.", model.Name)
+			}
+			return
+		}
+		log.Printf("%sRepository now hides soft-deleted rows and has a SoftDelete method.", model.Name)
+		addNextStep("Add a nullable %s column to %s, e.g. ALTER TABLE %s ADD COLUMN %s TIMESTAMP NULL;", column, repo.Table, repo.Table, column)
+	},
+}
+
+// softDeleteColumn returns the column of a model's DeletedAt field, if it has one.
+func softDeleteColumn(model utils.Model) (string, bool) {
+	for _, f := range model.Fields {
+		if f.Name == utils.SoftDeleteField {
+			return f.Column, true
+		}
+	}
+	return "", false
+}
+
+// addSoftDeleteMethod filters the queries of a generated SQL repository and
+// adds a SoftDelete method, unless it already has one.
+func addSoftDeleteMethod(path, modelName, column string) (utils.SQLRepository, error) {
+	repo, err := utils.ParseSQLRepository(path, modelName)
+	if err != nil || repo.HasSoftDelete {
+		return repo, err
+	}
+	method := fmt.Sprintf(`
+// SoftDelete marks the row with the given ID as deleted, or returns Err%[1]sNotFound.
+// The repository's other queries skip soft-deleted rows.
+func (%[2]s *%[1]sRepository) SoftDelete(ctx context.Context, id %[3]s) error {
+	res, err := %[2]s.db.ExecContext(ctx, "UPDATE %[4]s SET %[5]s = CURRENT_TIMESTAMP WHERE %[6]s = %[7]s AND %[5]s IS NULL", id)
+	if err != nil {
+		return err
+	}
+	return %[2]s.expectRow(res)
+}
+`, modelName, repo.Receiver, repo.IDType, goStringContent(repo.Table), column, repo.IDColumn, repo.Placeholder)
+	return repo, utils.AddSoftDeleteFilter(path, modelName, column, method)
+}
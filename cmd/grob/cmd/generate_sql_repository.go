@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	repoModel  string
+	repoTable  string
+	repoDriver string
+	repoID     string
+	repoSchema string
+)
+
+// sqlIdentifier matches the table and column names the generated queries may contain.
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlName matches a single unqualified identifier, such as a schema name.
+var sqlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// integerTypes are the ID types treated as database-generated keys.
+var integerTypes = map[string]bool{"int": true, "int32": true, "int64": true, "uint": true, "uint32": true, "uint64": true}
+
+func init() {
+	generateSQLRepositoryCmd.Flags().StringVar(&repoModel, "model", "", "model struct to persist (default: the only model in the module)")
+	generateSQLRepositoryCmd.Flags().StringVar(&repoTable, "table", "", "database table (default: the module name)")
+	generateSQLRepositoryCmd.Flags().StringVar(&repoDriver, "driver", "postgres", "SQL dialect for placeholders: postgres or mysql")
+	generateSQLRepositoryCmd.Flags().StringVar(&repoID, "id", "ID", "model field holding the primary key")
+	generateSQLRepositoryCmd.Flags().StringVar(&repoSchema, "schema", "", "schema the table is in, quoted with it in the queries (default: unqualified)")
+	generateCmd.AddCommand(generateSQLRepositoryCmd)
+}
+
+var generateSQLRepositoryCmd = &cobra.Command{
+	Use:   "sql-repository [app-name] [module-name]",
+	Short: "Generate a database/sql repository with CRUD queries for a module's model",
+	Example: `  grob generate sql-repository users profile --table profiles --driver mysql
+  grob generate sql-repository users profile --table profiles --schema tenant1`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating SQL repository for module '%s' in app '%s'", moduleName, appName)
+
+		if repoDriver != "postgres" && repoDriver != "mysql" {
+			log.Fatalf("Unknown driver %q: use postgres or mysql", repoDriver)
+		}
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		model, err := findModel(moduleDir, repoModel)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		table := repoTable
+		if table == "" {
+			table = data["ModuleName"]
+		}
+		if !sqlIdentifier.MatchString(table) {
+			log.Fatalf("Invalid table name %q", table)
+		}
+		if repoSchema != "" {
+			if !sqlName.MatchString(repoSchema) {
+				log.Fatalf("Invalid schema name %q", repoSchema)
+			}
+			if strings.Contains(table, ".") {
+				log.Fatalf("Table %q is already qualified; drop --schema or the schema in --table", table)
+			}
+		}
+		table = utils.QualifiedTable(repoDriver, repoSchema, table)
+		data["ModelName"] = model.Name
+		setRepoTable(data, table)
+		data["RepoDriver"] = repoDriver
+		if err := addRepositoryQueries(data, model, repoID); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		path := filepath.Join(moduleDir, fmt.Sprintf("%s.repository.go", strings.ToLower(model.Name)))
+		if _, err := os.Stat(path); err == nil {
+			log.Fatalf("%s already exists", path)
+		}
+		utils.CreateFileFromTmpl(path, templates.SQLRepositoryTmpl, data)
+		if column, ok := softDeleteColumn(model); ok {
+			if _, err := addSoftDeleteMethod(path, model.Name, column); err != nil {
+				log.Fatalf("Failed to add soft delete to %s: %v", path, err)
+			}
+			log.Printf("%s has a %s field, so the repository hides soft-deleted rows.", model.Name, utils.SoftDeleteField)
+		}
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", data["ModuleName"]))
+		ctor := "New" + model.Name + "Repository"
+		ok, err := utils.AddProviderToModule(modulePath, ctor)
+		if err != nil {
+			log.Fatalf("Failed to register %s: %v", ctor, err)
+		}
+		if !ok {
+			addProvideStep(ctor, modulePath)
+		}
+
+		log.Printf("%sRepository created in %s for table %s.", model.Name, path, table)
+		addNextStep("Provide a *sql.DB in the container, e.g. with 'grob generate graceful-db %s'.", appName)
+	},
+}
+
+// setRepoTable sets the table of the repository templates from table, as the
+// queries name it: RepoTable without quotes, for comments, and RepoTableSQL
+// escaped for the Go string literals holding the queries.
+func setRepoTable(data map[string]string, table string) {
+	data["RepoTable"] = utils.UnquoteTable(table)
+	data["RepoTableSQL"] = goStringContent(table)
+}
+
+// goStringContent escapes s for the inside of a Go interpreted string literal.
+func goStringContent(s string) string {
+	q := strconv.Quote(s)
+	return q[1 : len(q)-1]
+}
+
+// findModel returns the named model from the module's *.model.go files, or the
+// only model there when name is empty.
+func findModel(moduleDir, name string) (utils.Model, error) {
+	paths, err := filepath.Glob(filepath.Join(moduleDir, "*.model.go"))
+	if err != nil {
+		return utils.Model{}, err
+	}
+
+	var models []utils.Model
+	for _, path := range paths {
+		found, err := utils.ParseModels(path)
+		if err != nil {
+			return utils.Model{}, err
+		}
+		models = append(models, found...)
+	}
+
+	if name == "" {
+		switch len(models) {
+		case 0:
+			return utils.Model{}, fmt.Errorf("no model found in %s; create one with 'grob generate model'", moduleDir)
+		case 1:
+			return models[0], nil
+		default:
+			return utils.Model{}, fmt.Errorf("%s has several models; choose one with --model", moduleDir)
+		}
+	}
+	for _, m := range models {
+		if m.Name == utils.GoName(name) {
+			return m, nil
+		}
+	}
+	return utils.Model{}, fmt.Errorf("model %s not found in %s", utils.GoName(name), moduleDir)
+}
+
+// addRepositoryQueries fills in the column lists, placeholders and arguments of
+// the repository's queries. Integer IDs are left to the database to generate,
+// and so are the readOnly fields, which are read but never written.
+func addRepositoryQueries(data map[string]string, model utils.Model, idField string, readOnly ...string) error {
+	var id *utils.ModelField
+	var rest []utils.ModelField
+	for i, f := range model.Fields {
+		if !sqlIdentifier.MatchString(f.Column) {
+			return fmt.Errorf("field %s: invalid column name %q", f.Name, f.Column)
+		}
+		if f.Name == idField {
+			id = &model.Fields[i]
+			continue
+		}
+		// Only SoftDelete sets the soft-delete column.
+		if f.Name == utils.SoftDeleteField || slices.Contains(readOnly, f.Name) {
+			continue
+		}
+		rest = append(rest, f)
+	}
+	if id == nil {
+		return fmt.Errorf("model %s has no %s field; choose the key with --id", model.Name, idField)
+	}
+	if len(rest) == 0 {
+		return fmt.Errorf("model %s has no fields besides %s", model.Name, idField)
+	}
+
+	placeholder := func(n int) string {
+		if data["RepoDriver"] == "mysql" {
+			return "?"
+		}
+		return fmt.Sprintf("$%d", n)
+	}
+
+	var columns, scan []string
+	for _, f := range model.Fields {
+		columns = append(columns, f.Column)
+		scan = append(scan, "&m."+f.Name)
+	}
+
+	autoID := integerTypes[id.Type]
+	inserted := rest
+	if !autoID {
+		inserted = append([]utils.ModelField{*id}, rest...)
+	}
+	var insertColumns, insertValues, insertArgs []string
+	for i, f := range inserted {
+		insertColumns = append(insertColumns, f.Column)
+		insertValues = append(insertValues, placeholder(i+1))
+		insertArgs = append(insertArgs, "m."+f.Name)
+	}
+
+	var set, updateArgs []string
+	for i, f := range rest {
+		set = append(set, fmt.Sprintf("%s = %s", f.Column, placeholder(i+1)))
+		updateArgs = append(updateArgs, "m."+f.Name)
+	}
+	updateArgs = append(updateArgs, "m."+id.Name)
+
+	data["RepoIDField"] = id.Name
+	data["RepoIDColumn"] = id.Column
+	data["RepoIDType"] = id.Type
+	data["RepoAutoID"] = ""
+	if autoID {
+		data["RepoAutoID"] = "true"
+	}
+	data["RepoColumns"] = strings.Join(columns, ", ")
+	data["RepoScanArgs"] = strings.Join(scan, ", ")
+	data["RepoIDPlaceholder"] = placeholder(1)
+	data["RepoInsertColumns"] = strings.Join(insertColumns, ", ")
+	data["RepoInsertValues"] = strings.Join(insertValues, ", ")
+	data["RepoInsertArgs"] = strings.Join(insertArgs, ", ")
+	data["RepoUpdateSet"] = strings.Join(set, ", ")
+	data["RepoUpdateIDPlaceholder"] = placeholder(len(rest) + 1)
+	data["RepoUpdateArgs"] = strings.Join(updateArgs, ", ")
+	return nil
+}
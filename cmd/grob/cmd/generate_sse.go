@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateSSECmd)
+}
+
+var generateSSECmd = &cobra.Command{
+	Use:   "sse [app-name] [module-name]",
+	Short: "Generate a server-sent events stream and event source for a module",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		moduleName := args[1]
+		log.Printf("Generating SSE stream for module '%s' in app '%s'", moduleName, appName)
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		moduleName, title := data["ModuleName"], data["ModuleType"]
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+		if _, err := os.Stat(modulePath); err != nil {
+			log.Fatalf("Module '%s' not found in app '%s': %v", moduleName, appName, err)
+		}
+
+		files := map[string]string{
+			fmt.Sprintf("%s.events.go", moduleName): templates.EventSourceTmpl,
+			fmt.Sprintf("%s.sse.go", moduleName):    templates.SSEControllerTmpl,
+		}
+		for name := range files {
+			if _, err := os.Stat(filepath.Join(moduleDir, name)); err == nil {
+				log.Fatalf("%s already exists", filepath.Join(moduleDir, name))
+			}
+		}
+		for name, tmpl := range files {
+			utils.CreateFileFromTmpl(filepath.Join(moduleDir, name), tmpl, data)
+		}
+
+		for _, ctor := range []string{"New" + title + "EventSource", "New" + title + "SSEController"} {
+			ok, err := utils.AddProviderToModule(modulePath, ctor)
+			if err != nil {
+				log.Fatalf("Failed to register %s: %v", ctor, err)
+			}
+			if !ok {
+				addProvideStep(ctor, modulePath)
+			}
+		}
+
+		log.Printf("%sSSEController streams GET /events.", title)
+		addNextStep("Register %sSSEController's routes alongside %sController's.", title, title)
+		addNextStep("Inject *%sEventSource into %sService and call Publish to send events.", title, title)
+	},
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	uploadStorage  string
+	uploadMaxBytes int64
+	uploadTypes    []string
+	uploadRoute    string
+)
+
+// storageBackends maps each --storage value to its implementation template and
+// the modules it requires.
+var storageBackends = map[string]struct {
+	file, tmpl string
+	requires   [][2]string
+}{
+	"local": {"local.go", templates.StorageLocalTmpl, nil},
+	"s3": {"s3.go", templates.StorageS3Tmpl, [][2]string{
+		{"github.com/aws/aws-sdk-go-v2", "v1.30.3"},
+		{"github.com/aws/aws-sdk-go-v2/config", "v1.27.27"},
+		{"github.com/aws/aws-sdk-go-v2/feature/s3/manager", "v1.17.10"},
+		{"github.com/aws/aws-sdk-go-v2/service/s3", "v1.58.3"},
+	}},
+}
+
+func init() {
+	generateStreamingUploadCmd.Flags().StringVar(&uploadStorage, "storage", "local", "where files are stored: local or s3")
+	generateStreamingUploadCmd.Flags().Int64Var(&uploadMaxBytes, "max-bytes", 100<<20, "largest file accepted, in bytes")
+	generateStreamingUploadCmd.Flags().StringSliceVar(&uploadTypes, "types", []string{"image/jpeg", "image/png", "application/pdf"}, "content types accepted, as detected from the file's first bytes")
+	generateStreamingUploadCmd.Flags().StringVar(&uploadRoute, "route", "/uploads", "route of the handler on the module's router group")
+	generateCmd.AddCommand(generateStreamingUploadCmd)
+}
+
+var generateStreamingUploadCmd = &cobra.Command{
+	Use:   "streaming-upload [app-name] [module-name]",
+	Short: "Generate a handler that streams multipart file uploads to local disk or S3",
+	Long: `Generate an upload controller for a module, and the shared pkg/storage:
+
+  pkg/storage                   the Storage interface, a LocalStorage or
+                                S3Storage, and StorageModule providing it
+  <module>/<module>.upload.go   <Module>UploadController handling POST --route
+
+The handler reads the multipart body part by part and streams the "file" part
+to storage as it arrives, so memory use does not grow with the file. Files
+larger than --max-bytes get 413, and files whose content type, detected from
+their first bytes, is not one of --types get 415. Files are stored under a
+random key, returned in the response.
+
+StorageModule is registered in the app's main.go. Local files go under
+STORAGE_DIR; S3 objects go to STORAGE_BUCKET, optionally on the S3-compatible
+service at STORAGE_ENDPOINT.`,
+	Example: `  grob generate streaming-upload media assets
+  grob generate streaming-upload media assets --storage s3 --max-bytes 1073741824 --types video/mp4,video/webm`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating streaming upload for module '%s' in app '%s'", moduleName, appName)
+
+		backend, ok := storageBackends[uploadStorage]
+		if !ok {
+			log.Fatalf("Unknown --storage %q: use local or s3", uploadStorage)
+		}
+		if uploadMaxBytes <= 0 {
+			log.Fatal("--max-bytes must be positive")
+		}
+		if !strings.HasPrefix(uploadRoute, "/") {
+			log.Fatalf("Invalid --route %q: it must start with /", uploadRoute)
+		}
+		contentTypes, err := uploadContentTypes(uploadTypes)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		moduleName, title := data["ModuleName"], data["ModuleType"]
+		if _, err := os.Stat(filepath.Join(projectRoot, "internal", appName, "core")); err != nil {
+			log.Fatalf("App '%s' has no dependency injection container to provide the storage with", appName)
+		}
+		uploadPath := filepath.Join(moduleDir, fmt.Sprintf("%s.upload.go", moduleName))
+		if _, err := os.Stat(uploadPath); err == nil {
+			log.Fatalf("%s already exists", uploadPath)
+		}
+
+		// Answer in the style of the module's other handlers.
+		data["ResponseFormat"] = "raw"
+		controller, err := os.ReadFile(filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName)))
+		if err == nil && bytes.Contains(controller, []byte(strconv.Quote(data["ProjectName"]+"/pkg/response"))) {
+			data["ResponseFormat"] = "envelope"
+		}
+		data["StorageBackend"] = uploadStorage
+		data["UploadMaxBytes"] = strconv.FormatInt(uploadMaxBytes, 10)
+		data["UploadRoute"] = uploadRoute
+		data["UploadContentTypes"] = contentTypes
+
+		var files createdFiles
+		err = ensureStoragePackage(projectRoot, data, backend.file, backend.tmpl, backend.requires, &files)
+		if err == nil {
+			err = files.tmpl(uploadPath, templates.UploadControllerTmpl, data)
+		}
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		mainPath := appMainPath(projectRoot, appName)
+		importPath := data["ProjectName"] + "/pkg/storage"
+		modules, err := utils.ParseAppModules(mainPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", mainPath, err)
+		}
+		registered := false
+		for _, m := range modules {
+			registered = registered || m.ImportPath == importPath
+		}
+		if !registered {
+			if err := utils.AddModuleToAppMain(mainPath, importPath, "storage", "Storage"); err != nil {
+				log.Fatalf("Failed to register StorageModule: %v", err)
+			}
+		}
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+		ctor := "New" + title + "UploadController"
+		ok, err = utils.AddProviderToModule(modulePath, ctor)
+		if err != nil {
+			log.Fatalf("Failed to register %s: %v", ctor, err)
+		}
+		if !ok {
+			addProvideStep(ctor, modulePath)
+		}
+
+		log.Printf("%sUploadController created in %s.", title, uploadPath)
+		addNextStep("Register %sUploadController's routes alongside %sController's.", title, title)
+		if uploadStorage == "s3" {
+			addNextStep("Set STORAGE_BUCKET and the AWS region and credentials, or STORAGE_ENDPOINT for an S3-compatible service.")
+			addNextStep("Run 'go mod tidy'.")
+		} else {
+			addNextStep("Set STORAGE_DIR to where files should be kept; it defaults to ./uploads.")
+		}
+	},
+}
+
+// uploadContentTypes validates the --types values and renders them as the
+// entries of the generated UploadContentTypes map.
+func uploadContentTypes(types []string) (string, error) {
+	if len(types) == 0 {
+		return "", fmt.Errorf("--types needs at least one content type")
+	}
+	var lines []string
+	seen := map[string]bool{}
+	for _, t := range types {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(t))
+		if err != nil || len(params) > 0 || !strings.Contains(mediaType, "/") || strings.Contains(mediaType, "*") {
+			return "", fmt.Errorf("invalid --types value %q: use a content type such as image/png", t)
+		}
+		if !seen[mediaType] {
+			seen[mediaType] = true
+			lines = append(lines, fmt.Sprintf("\t%q: true,", mediaType))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ensureStoragePackage creates the shared pkg/storage with the given backend,
+// unless it already exists.
+func ensureStoragePackage(projectRoot string, data map[string]string, file, tmpl string, requires [][2]string, files *createdFiles) error {
+	dir := filepath.Join(projectRoot, "pkg", "storage")
+	if _, err := os.Stat(dir); err == nil {
+		log.Printf("%s already exists; reusing it.", dir)
+		return nil
+	}
+	if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+		return fmt.Errorf("failed to create storage package: %w", err)
+	}
+	for _, f := range [][2]string{{"storage.go", templates.StorageTmpl}, {file, tmpl}, {"module.go", templates.StorageModuleTmpl}} {
+		if err := files.tmpl(filepath.Join(dir, f[0]), f[1], data); err != nil {
+			return err
+		}
+	}
+	for _, req := range requires {
+		if err := utils.AddRequire(projectRoot, req[0], req[1]); err != nil {
+			return fmt.Errorf("failed to update go.mod: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateTracingCmd)
+}
+
+var generateTracingCmd = &cobra.Command{
+	Use:   "tracing [app-name]",
+	Short: "Generate OpenTelemetry tracing with an OTLP exporter and a span per request for an app",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Generating tracing for app '%s'", appName)
+
+		projectRoot, data := loadApp(appName)
+
+		tracingDir := filepath.Join(projectRoot, "internal", appName, "tracing")
+		createPackageDir(tracingDir)
+		utils.CreateFileFromTmpl(filepath.Join(tracingDir, "tracing.go"), templates.TracingTmpl, data)
+
+		for _, mod := range []string{
+			"go.opentelemetry.io/otel",
+			"go.opentelemetry.io/otel/trace",
+			"go.opentelemetry.io/otel/sdk",
+			"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc",
+		} {
+			if err := utils.AddRequire(projectRoot, mod, "v1.16.0"); err != nil {
+				log.Fatalf("Failed to update go.mod: %v", err)
+			}
+		}
+
+		importPath := fmt.Sprintf("%s/internal/%s/tracing", data["ProjectName"], appName)
+		mainPath := appMainPath(projectRoot, appName)
+		if err := utils.AddMiddlewareToAppMain(mainPath, "", importPath, "app.Router().Use(tracing.Middleware())"); err != nil {
+			log.Fatalf("Failed to wire tracing: %v", err)
+		}
+		if err := utils.AddStatementToAppMain(mainPath, "", importPath, "defer tracing.Setup()()"); err != nil {
+			log.Fatalf("Failed to wire tracing: %v", err)
+		}
+
+		log.Printf("Tracing created in %s; every request now gets a span.", tracingDir)
+		addNextStep("Run 'go mod tidy' to download the OpenTelemetry SDK.")
+		addNextStep("Set OTEL_EXPORTER_OTLP_ENDPOINT (e.g. http://localhost:4317) to export spans to a collector.")
+		addNextStep("Create child spans in services with tracing.Start(ctx, \"name\"); modules created with --ctx receive the request context.")
+	},
+}
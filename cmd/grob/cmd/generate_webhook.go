@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var (
+	webhookSigHeader string
+	webhookAlgo      string
+	webhookEncoding  string
+	webhookRoute     string
+	webhookMaxBytes  int64
+)
+
+func init() {
+	generateWebhookCmd.Flags().StringVar(&webhookSigHeader, "sig-header", "X-Signature", "request header carrying the HMAC signature")
+	generateWebhookCmd.Flags().StringVar(&webhookAlgo, "algo", "sha256", "HMAC hash: sha1, sha256, or sha512")
+	generateWebhookCmd.Flags().StringVar(&webhookEncoding, "encoding", "hex", "encoding of the signature: hex or base64")
+	generateWebhookCmd.Flags().StringVar(&webhookRoute, "route", "/webhook", "route of the handler on the module's router group")
+	generateWebhookCmd.Flags().Int64Var(&webhookMaxBytes, "max-bytes", 1<<20, "largest request body accepted, in bytes")
+	generateCmd.AddCommand(generateWebhookCmd)
+}
+
+var generateWebhookCmd = &cobra.Command{
+	Use:   "webhook-receiver [app-name] [module-name]",
+	Short: "Generate a POST handler that verifies HMAC-signed webhooks and passes their events to a module's service",
+	Long: `Generate a webhook handler on a module's controller and a HandleWebhook
+service method. The handler reads the raw body, checks the HMAC signature in
+the --sig-header header against the secret in <APP>_<MODULE>_WEBHOOK_SECRET
+with a constant-time comparison, and only then parses the event and passes it
+to the service. Requests with a missing or wrong signature get 401.`,
+	Example: `  grob generate webhook-receiver api stripe --sig-header Stripe-Signature
+  grob generate webhook-receiver api github --sig-header X-Hub-Signature-256 --route /events`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName, moduleName := args[0], args[1]
+		log.Printf("Generating webhook receiver for module '%s' in app '%s'", moduleName, appName)
+
+		if webhookAlgo != "sha1" && webhookAlgo != "sha256" && webhookAlgo != "sha512" {
+			log.Fatalf("Unknown --algo %q: use sha1, sha256, or sha512", webhookAlgo)
+		}
+		if webhookEncoding != "hex" && webhookEncoding != "base64" {
+			log.Fatalf("Unknown --encoding %q: use hex or base64", webhookEncoding)
+		}
+		if strings.TrimSpace(webhookSigHeader) == "" || strings.ContainsAny(webhookSigHeader, " :\"") {
+			log.Fatalf("Invalid --sig-header %q: use a header name such as X-Signature", webhookSigHeader)
+		}
+		if !strings.HasPrefix(webhookRoute, "/") {
+			log.Fatalf("Invalid --route %q: it must start with /", webhookRoute)
+		}
+		if webhookMaxBytes <= 0 {
+			log.Fatal("--max-bytes must be positive")
+		}
+
+		_, data, moduleDir := loadModule(appName, moduleName)
+		moduleName, title := data["ModuleName"], data["ModuleType"]
+		data["WebhookHeader"] = http.CanonicalHeaderKey(webhookSigHeader)
+		data["WebhookHash"] = webhookAlgo
+		data["WebhookHashName"] = strings.ToUpper(webhookAlgo)
+		data["WebhookEncoding"] = webhookEncoding
+		data["WebhookRoute"] = webhookRoute
+		data["WebhookMaxBytes"] = fmt.Sprint(webhookMaxBytes)
+		data["WebhookSecretEnv"] = utils.EnvPrefix(appName) + "_" + utils.EnvPrefix(moduleName) + "_WEBHOOK_SECRET"
+
+		servicePath := filepath.Join(moduleDir, fmt.Sprintf("%s.service.go", moduleName))
+		controllerPath := filepath.Join(moduleDir, fmt.Sprintf("%s.controller.go", moduleName))
+		for _, path := range []string{servicePath, controllerPath} {
+			if _, err := os.Stat(path); err != nil {
+				log.Fatalf("%s not found: the webhook receiver extends the module's controller and service", path)
+			}
+		}
+		// Answer in the style of the module's other handlers.
+		controller, err := os.ReadFile(controllerPath)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", controllerPath, err)
+		}
+		data["ResponseFormat"] = "raw"
+		if bytes.Contains(controller, []byte(strconv.Quote(data["ProjectName"]+"/pkg/response"))) {
+			data["ResponseFormat"] = "envelope"
+		}
+
+		webhookPath := filepath.Join(moduleDir, fmt.Sprintf("%s.webhook.go", moduleName))
+		if _, err := os.Stat(webhookPath); err == nil {
+			log.Fatalf("%s already exists", webhookPath)
+		}
+		// A controller switched to the service interface by 'grob generate cache'
+		// needs HandleWebhook on the interface, and the cached service forwards it.
+		data["WebhookCached"] = ""
+		if _, err := os.Stat(filepath.Join(moduleDir, fmt.Sprintf("%s.cache.go", moduleName))); err == nil {
+			data["WebhookCached"] = "true"
+		}
+		utils.CreateFileFromTmpl(webhookPath, templates.WebhookTmpl, data)
+		interfacePath := filepath.Join(moduleDir, fmt.Sprintf("%s.interface.go", moduleName))
+		if _, err := os.Stat(interfacePath); err == nil {
+			method := fmt.Sprintf("HandleWebhook(ctx context.Context, event %sWebhookEvent) error", title)
+			if _, err := utils.AddMethodToInterface(interfacePath, title+"ServiceInterface", method, "context"); err != nil {
+				log.Fatalf("Failed to add HandleWebhook to %sServiceInterface: %v", title, err)
+			}
+		}
+
+		ok, err := utils.AddRouteToController(controllerPath, title+"Controller", "POST", webhookRoute, "Webhook")
+		if err != nil {
+			log.Fatalf("Failed to add the webhook route: %v", err)
+		}
+		if !ok {
+			addNextStep("Route POST %s to %sController.Webhook; %s has no RegisterRoutes method.", webhookRoute, title, controllerPath)
+		}
+
+		log.Printf("Webhook receiver created in %s and routed at POST %s.", webhookPath, webhookRoute)
+		addNextStep("Set %s to the signing secret shared with the sender.", data["WebhookSecretEnv"])
+		addNextStep("Handle the event types you subscribe to in %sService.HandleWebhook.", title)
+		addNextStep("If the sender signs more than the raw body (e.g. a timestamp and the body), adjust webhookSignedPayload in %s.", webhookPath)
+	},
+}
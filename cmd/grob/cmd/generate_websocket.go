@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	generateCmd.AddCommand(generateWebSocketCmd)
+}
+
+var generateWebSocketCmd = &cobra.Command{
+	Use:   "websocket [app-name] [module-name]",
+	Short: "Generate a WebSocket endpoint and connection hub for a module",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		moduleName := args[1]
+		log.Printf("Generating WebSocket endpoint for module '%s' in app '%s'", moduleName, appName)
+
+		projectRoot, data, moduleDir := loadModule(appName, moduleName)
+		moduleName, title := data["ModuleName"], data["ModuleType"]
+
+		modulePath := filepath.Join(moduleDir, fmt.Sprintf("%s.module.go", moduleName))
+		if _, err := os.Stat(modulePath); err != nil {
+			log.Fatalf("Module '%s' not found in app '%s': %v", moduleName, appName, err)
+		}
+
+		files := map[string]string{
+			fmt.Sprintf("%s.hub.go", moduleName):       templates.WebSocketHubTmpl,
+			fmt.Sprintf("%s.websocket.go", moduleName): templates.WebSocketControllerTmpl,
+		}
+		for name := range files {
+			if _, err := os.Stat(filepath.Join(moduleDir, name)); err == nil {
+				log.Fatalf("%s already exists", filepath.Join(moduleDir, name))
+			}
+		}
+		for name, tmpl := range files {
+			utils.CreateFileFromTmpl(filepath.Join(moduleDir, name), tmpl, data)
+		}
+
+		if err := utils.AddRequire(projectRoot, "github.com/gorilla/websocket", "v1.5.3"); err != nil {
+			log.Fatalf("Failed to update go.mod: %v", err)
+		}
+
+		for _, ctor := range []string{"New" + title + "Hub", "New" + title + "WebSocketController"} {
+			ok, err := utils.AddProviderToModule(modulePath, ctor)
+			if err != nil {
+				log.Fatalf("Failed to register %s: %v", ctor, err)
+			}
+			if !ok {
+				addProvideStep(ctor, modulePath)
+			}
+		}
+
+		log.Printf("%sWebSocketController upgrades GET /ws.", title)
+		addNextStep("Register %sWebSocketController's routes alongside %sController's.", title, title)
+		addNextStep("Inject *%sHub into %sService and call Broadcast to push messages to clients.", title, title)
+	},
+}
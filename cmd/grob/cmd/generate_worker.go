@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	generateWorkerCmd.Flags().StringVar(&appQueue, "queue", "nats", "message broker to consume from: nats, kafka, or rabbitmq")
+	generateCmd.AddCommand(generateWorkerCmd)
+}
+
+var generateWorkerCmd = &cobra.Command{
+	Use:   "worker [app-name]",
+	Short: "Generate a background worker app that consumes from a message queue",
+	Long:  `Generate a background worker app. This is a shorthand for 'grob create-app [app-name] --type worker'.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		appName := args[0]
+		log.Printf("Creating new worker application: %s", appName)
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		appType = "worker"
+		files, err := createApp(projectRoot, appName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportCreated(projectRoot, files)
+	},
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var noHistory bool
+
+// historyRoot is the project a command generated into when it is not the one
+// findProjectRoot returns, as for 'grob new'.
+var historyRoot string
+
+// historyCommands are the top-level commands that generate code, and whose
+// runs are recorded in the project history.
+var historyCommands = map[string]bool{
+	"new": true, "create-app": true, "create-module": true, "scaffold": true, "generate": true,
+}
+
+// secretFlag matches the names of flags whose values may hold credentials,
+// such as --dsn or --base-url, or arbitrary user input, such as --var. Their
+// values are never written to the history.
+var secretFlag = regexp.MustCompile(`(?i)secret|passw|token|key|dsn|url|credential|auth|var`)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "do not record the command in the project's "+utils.HistoryFile)
+}
+
+// recordHistory appends the command that just finished to the project's
+// history, if it is a generating command. Like printNextSteps it runs after
+// every command, so commands that exit with log.Fatal are not recorded.
+func recordHistory(cmd *cobra.Command, args []string) {
+	if noHistory || !historyCommands[topLevelName(cmd)] {
+		return
+	}
+	if dryRun, err := cmd.Flags().GetBool("dry-run"); err == nil && dryRun {
+		return
+	}
+	root := commandRoot()
+	if root == "" {
+		return
+	}
+
+	entry := utils.HistoryEntry{
+		Time:         time.Now().UTC().Truncate(time.Second),
+		Command:      strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Args:         args,
+		CreatedFiles: projectFiles(root),
+	}
+	inherited := cmd.InheritedFlags()
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		// Global flags such as --json say nothing about what was generated.
+		if inherited.Lookup(f.Name) != nil {
+			return
+		}
+		if entry.Flags == nil {
+			entry.Flags = map[string]string{}
+		}
+		value := f.Value.String()
+		if f.Value.Type() != "bool" && secretFlag.MatchString(f.Name) {
+			value = utils.RedactedValue
+		}
+		entry.Flags[f.Name] = value
+	})
+	if err := utils.AppendHistory(root, entry); err != nil {
+		log.Printf("Warning: failed to record the command in %s: %v", filepath.FromSlash(utils.HistoryFile), err)
+	}
+}
+
+// commandRoot returns the project the command generated into, or "" if it
+// did not run in a project.
+func commandRoot() string {
+	if historyRoot != "" {
+		return historyRoot
+	}
+	root, err := findProjectRoot()
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// topLevelName returns the name of the command's ancestor directly under the
+// root command, e.g. "generate" for 'grob generate cache'.
+func topLevelName(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
@@ -1,16 +1,38 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/yuliussmayoru/grob-cli/internal/templates"
 	"github.com/yuliussmayoru/grob-cli/internal/utils"
 )
 
+var (
+	newLayout           string
+	newFrameworkReplace string
+	newFrameworkVersion string
+	newOffline          bool
+	newReadme           bool
+	newReadmeTemplate   string
+	newPrivateRepos     []string
+	newWorkspace        bool
+)
+
 func init() {
+	newCmd.Flags().StringVar(&newLayout, "layout", "shared", `project layout: "shared" runs all apps from internal/main.go, "binaries" builds each app under cmd/<app>`)
+	newCmd.Flags().StringVar(&newFrameworkReplace, "framework-replace", "", "add a replace directive pointing grob-framework at a local checkout, e.g. ../grob-framework")
+	newCmd.Flags().StringVar(&newFrameworkVersion, "framework-version", "", "grob-framework version to require in go.mod (default: framework-version from 'grob config', or v0.1.0)")
+	newCmd.Flags().BoolVar(&newOffline, "offline", false, "skip the post-create build that checks grob-framework compatibility")
+	newCmd.Flags().BoolVar(&newReadme, "readme", true, "generate a README.md for the project")
+	newCmd.Flags().StringVar(&newReadmeTemplate, "readme-template", "", "template file to generate README.md from instead of the built-in one")
+	newCmd.Flags().BoolVar(&newWorkspace, "workspace", false, "create a go.work workspace in which each app created later is a module of its own")
+	newCmd.Flags().StringSliceVar(&newPrivateRepos, "private-repos", nil, `GOPRIVATE patterns for private module dependencies, e.g. "github.com/acme/*"`)
 	rootCmd.AddCommand(newCmd)
 }
 
@@ -22,27 +44,143 @@ var newCmd = &cobra.Command{
 		projectName := args[0]
 		log.Printf("Creating new project: %s", projectName)
 
-		if err := os.Mkdir(projectName, 0755); err != nil {
-			log.Fatalf("Failed to create project directory: %v", err)
+		// A full module path such as github.com/acme/shop is created in ./shop.
+		projectDir := path.Base(projectName)
+		files, err := newProject(projectName, projectDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reportCreated(projectDir, files)
+		historyRoot = projectDir
+
+		if !newOffline {
+			checkFramework(projectDir)
+		}
+
+		log.Printf("Project '%s' created successfully.", projectName)
+		addNextStep("cd %s", projectDir)
+		addNextStep("grob create-app myapp")
+		addNextStep("go mod tidy  # To download dependencies")
+	},
+}
+
+// newProject creates a project in projectDir. It returns the files it wrote,
+// including those written before an error.
+func newProject(projectName, projectDir string) ([]string, error) {
+	if newLayout != "shared" && newLayout != "binaries" {
+		return nil, fmt.Errorf("unknown layout %q: use shared or binaries", newLayout)
+	}
+	goPrivate := strings.Join(newPrivateRepos, ",")
+	if err := utils.ValidateGoPrivate(goPrivate); err != nil {
+		return nil, err
+	}
+
+	// Machine-wide defaults from 'grob config' fill in what the flags leave unset.
+	global, err := utils.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	goVersion := configValue(global, "go-version")
+	frameworkVersion := newFrameworkVersion
+	if frameworkVersion == "" {
+		frameworkVersion = configValue(global, "framework-version")
+	}
+
+	if err := os.Mkdir(projectDir, utils.DirMode); err != nil {
+		return nil, fmt.Errorf("failed to create project directory: %w", err)
+	}
+
+	dirs := []string{
+		filepath.Join(projectDir, "internal"),
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, utils.DirMode); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
+	}
 
-		dirs := []string{
-			filepath.Join(projectName, "internal"),
+	if newFrameworkReplace != "" {
+		// Relative replace paths are resolved from the project directory, like go does.
+		target := newFrameworkReplace
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(projectDir, target)
 		}
-		for _, dir := range dirs {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				log.Fatalf("Failed to create directory %s: %v", dir, err)
+		if _, err := os.Stat(filepath.Join(target, "go.mod")); err != nil {
+			if abs, err := filepath.Abs(target); err == nil {
+				target = abs
 			}
+			log.Printf("Warning: %s does not look like a grob-framework checkout (no go.mod found); the build will fail until it exists.", target)
 		}
+	}
 
-		utils.CreateFileFromTmpl(filepath.Join(projectName, "go.mod"), templates.GoModTmpl, map[string]string{"ProjectName": projectName})
-		utils.CreateFileFromTmpl(filepath.Join(projectName, ".gitignore"), templates.GitignoreTmpl, nil)
-		utils.CreateFileFromTmpl(filepath.Join(projectName, "internal", "main.go"), templates.InternalMainTmpl, nil)
+	var files createdFiles
+	err = files.tmpl(filepath.Join(projectDir, "go.mod"), templates.GoModTmpl, map[string]string{
+		"ProjectName":      projectName,
+		"GoVersion":        goVersion,
+		"FrameworkReplace": filepath.ToSlash(newFrameworkReplace),
+		"FrameworkVersion": frameworkVersion,
+	})
+	if err != nil {
+		return files, err
+	}
+	if err := files.tmpl(filepath.Join(projectDir, ".gitignore"), templates.GitignoreTmpl, nil); err != nil {
+		return files, err
+	}
+	if newWorkspace {
+		err := files.tmpl(filepath.Join(projectDir, utils.WorkFileName), templates.GoWorkTmpl, map[string]string{
+			"GoVersion": goVersion,
+		})
+		if err != nil {
+			return files, err
+		}
+	}
+	if newLayout == "binaries" || goPrivate != "" || newWorkspace {
+		workspace := ""
+		if newWorkspace {
+			workspace = "true"
+		}
+		err := files.tmpl(filepath.Join(projectDir, utils.ConfigFileName), templates.GrobrcTmpl, map[string]string{
+			"Layout":    newLayout,
+			"GoPrivate": goPrivate,
+			"Workspace": workspace,
+		})
+		if err != nil {
+			return files, err
+		}
+	}
+	if newLayout != "binaries" {
+		if err := files.tmpl(filepath.Join(projectDir, "internal", "main.go"), templates.InternalMainTmpl, nil); err != nil {
+			return files, err
+		}
+	}
 
-		log.Printf("Project '%s' created successfully.", projectName)
-		log.Println("Next steps:")
-		log.Printf("  cd %s", projectName)
-		log.Println("  grob create-app myapp")
-		log.Println("  go mod tidy  # To download dependencies")
-	},
+	if newReadme {
+		if err := writeReadme(projectDir, projectName, goPrivate, global, &files); err != nil {
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// writeReadme generates README.md from --readme-template, or the built-in
+// template. Templates receive ProjectName, ProjectTitle, Layout, GoPrivate,
+// and the Author and License set with 'grob config'.
+func writeReadme(projectDir, projectName, goPrivate string, global *utils.Config, files *createdFiles) error {
+	data := map[string]string{
+		"ProjectName":  projectName,
+		"ProjectTitle": path.Base(projectName),
+		"Layout":       newLayout,
+		"GoPrivate":    goPrivate,
+		"Author":       global.Author,
+		"License":      global.License,
+	}
+	readmePath := filepath.Join(projectDir, "README.md")
+	if newReadmeTemplate == "" {
+		return files.tmpl(readmePath, templates.ReadmeTmpl, data)
+	}
+	tmpl, err := os.ReadFile(newReadmeTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to read README template: %w", err)
+	}
+	return files.customTmpl(readmePath, newReadmeTemplate, string(tmpl), data)
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var jsonOutput bool
+
+// nextSteps are the manual follow-ups a command collects while it generates
+// code, such as environment variables to set or code generators to run.
+var nextSteps []string
+
+// addNextStep records a manual follow-up to print once the command finishes.
+// Continuation lines of a multi-line step, e.g. a code example, are indented
+// under it.
+func addNextStep(format string, args ...any) {
+	nextSteps = append(nextSteps, fmt.Sprintf(format, args...))
+}
+
+// addProvideStep asks for ctor to be provided by hand when it could not be
+// added to the module at modulePath, which has no Register method.
+func addProvideStep(ctor, modulePath string) {
+	addNextStep("Provide %s in the dependency injection container; %s has no Register method.", ctor, modulePath)
+}
+
+// printNextSteps prints the collected steps as a numbered list, or with --json
+// as a JSON object on stdout with the steps in a "next_steps" array and the
+// files the command created in "created_files". It runs
+// after every command; commands that exit with log.Fatal print nothing.
+func printNextSteps(cmd *cobra.Command, args []string) {
+	if jsonOutput {
+		steps := nextSteps
+		if steps == nil {
+			steps = []string{}
+		}
+		var created []string
+		if root := commandRoot(); root != "" {
+			created = projectFiles(root)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			Command      string   `json:"command"`
+			CreatedFiles []string `json:"created_files,omitempty"`
+			NextSteps    []string `json:"next_steps"`
+		}{strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "), created, steps})
+		if err != nil {
+			log.Fatalf("Failed to write JSON output: %v", err)
+		}
+		return
+	}
+
+	if len(nextSteps) == 0 {
+		return
+	}
+	log.Println("Next steps:")
+	for i, step := range nextSteps {
+		lines := strings.Split(step, "\n")
+		log.Printf("  %d. %s", i+1, lines[0])
+		for _, line := range lines[1:] {
+			log.Printf("     %s", line)
+		}
+	}
+}
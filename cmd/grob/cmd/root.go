@@ -3,14 +3,23 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
 )
 
+var projectRootFlag string
+
 var rootCmd = &cobra.Command{
 	Use:   "grob",
 	Short: "Grob is the official CLI for the Grob Framework",
 	Long:  `A powerful command-line tool to help you scaffold and manage your Grob projects.`,
+
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		recordHistory(cmd, args)
+		printNextSteps(cmd, args)
+	},
 }
 
 func Execute() {
@@ -21,7 +30,23 @@ func Execute() {
 }
 
 func init() {
-	// Here you will define your flags and configuration settings.
-	// Example:
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.grob-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&projectRootFlag, "project-root", "", "path to the Grob project (default is to search upwards from the current directory)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "print the command's next manual steps as JSON on stdout")
+}
+
+// findProjectRoot returns the project root given with --project-root, or
+// searches upwards from the current directory when the flag is not set.
+func findProjectRoot() (string, error) {
+	if projectRootFlag == "" {
+		return utils.FindProjectRoot()
+	}
+
+	root, err := filepath.Abs(projectRootFlag)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+		return "", fmt.Errorf("go.mod not found in --project-root %s", root)
+	}
+	return root, nil
 }
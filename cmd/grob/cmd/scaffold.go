@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+func init() {
+	rootCmd.AddCommand(scaffoldCmd)
+}
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold [spec-file]",
+	Short: "Create the apps and modules declared in a spec file",
+	Long: `Create the apps and modules declared in a YAML spec file:
+
+  apps:
+    - name: api
+      modules: [users, orders]
+
+Apps and modules that already exist are left untouched; nothing is ever deleted.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		spec, err := utils.LoadSpec(args[0])
+		if err != nil {
+			log.Fatalf("Failed to load spec: %v", err)
+		}
+
+		files, err := applySpec(projectRoot, spec)
+		reportCreated(projectRoot, files)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(files) == 0 {
+			log.Println("Project already matches the spec.")
+		}
+	},
+}
+
+// applySpec creates every app and module in spec that does not exist yet.
+// It returns the files it wrote, including those written before an error.
+func applySpec(projectRoot string, spec *utils.Spec) ([]string, error) {
+	var created []string
+	dirStyle := utils.TemplateData(projectRoot)["DirStyle"]
+	for _, app := range spec.Apps {
+		appDir := filepath.Join(projectRoot, "internal", app.Name)
+		if _, err := os.Stat(appDir); os.IsNotExist(err) {
+			files, err := createApp(projectRoot, app.Name)
+			created = append(created, files...)
+			if err != nil {
+				return created, fmt.Errorf("app %s: %w", app.Name, err)
+			}
+		}
+
+		for _, module := range app.Modules {
+			pkgName, _ := utils.ModuleNames(module)
+			if _, err := os.Stat(utils.ModuleDir(projectRoot, app.Name, pkgName, dirStyle)); !os.IsNotExist(err) {
+				continue
+			}
+			files, err := createModule(projectRoot, app.Name, module)
+			created = append(created, files...)
+			if err != nil {
+				return created, fmt.Errorf("module %s/%s: %w", app.Name, module, err)
+			}
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,8 @@
+module github.com/yuliussmayoru/grob-framework
+
+go 1.19
+
+require (
+	github.com/gin-gonic/gin v1.8.1
+	go.uber.org/dig v1.15.0
+)
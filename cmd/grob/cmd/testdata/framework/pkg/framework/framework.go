@@ -0,0 +1,35 @@
+// Package framework is the part of the grob-framework API that generated
+// projects use, for tests that build them without downloading it.
+package framework
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/dig"
+)
+
+// Module provides its components to an app's container.
+type Module interface {
+	Register(container *dig.Container) error
+}
+
+// App holds an app's router and dependency injection container.
+type App struct {
+	router    *gin.Engine
+	container *dig.Container
+}
+
+// New creates an app with the given modules registered.
+func New(modules ...Module) *App {
+	app := &App{router: gin.New(), container: dig.New()}
+	for _, m := range modules {
+		if err := m.Register(app.container); err != nil {
+			panic(err)
+		}
+	}
+	return app
+}
+
+// Router returns the app's router.
+func (a *App) Router() *gin.Engine {
+	return a.router
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/yuliussmayoru/grob-cli/internal/utils"
+)
+
+var watchDebounce time.Duration
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 300*time.Millisecond, "time to wait for further edits before re-scaffolding")
+	rootCmd.AddCommand(watchCmd)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [spec-file]",
+	Short: "Re-run scaffold whenever a spec file changes",
+	Long: `Watch a spec file and create newly declared apps and modules whenever it changes.
+Existing code is never modified or deleted. See 'grob scaffold --help' for the spec format.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		specPath, err := filepath.Abs(args[0])
+		if err != nil {
+			log.Fatalf("Invalid spec path: %v", err)
+		}
+
+		projectRoot, err := findProjectRoot()
+		if err != nil {
+			log.Fatalf("Error: %v. Make sure you are inside a Grob project.", err)
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			log.Fatalf("Failed to start file watcher: %v", err)
+		}
+		defer watcher.Close()
+
+		// Watch the directory rather than the file, since editors often save by
+		// replacing the file, which would drop a watch on the file itself.
+		if err := watcher.Add(filepath.Dir(specPath)); err != nil {
+			log.Fatalf("Failed to watch %s: %v", specPath, err)
+		}
+
+		syncSpec(projectRoot, specPath)
+		log.Printf("Watching %s for changes. Press Ctrl+C to stop.", specPath)
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != specPath || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				debounce = time.After(watchDebounce)
+			case <-debounce:
+				debounce = nil
+				syncSpec(projectRoot, specPath)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("Watcher error: %v", err)
+			}
+		}
+	},
+}
+
+// syncSpec loads the spec and creates anything new, logging instead of exiting on errors
+// so the watcher keeps running while the spec is being edited.
+func syncSpec(projectRoot, specPath string) {
+	spec, err := utils.LoadSpec(specPath)
+	if err != nil {
+		log.Printf("Skipping update: %v", err)
+		return
+	}
+
+	created, err := applySpec(projectRoot, spec)
+	for _, item := range created {
+		log.Printf("Created %s", item)
+	}
+	if err != nil {
+		log.Printf("Failed to apply spec: %v", err)
+		return
+	}
+	if len(created) == 0 {
+		log.Println("Spec up to date; nothing to create.")
+	}
+}
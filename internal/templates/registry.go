@@ -0,0 +1,602 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Funcs are the helper functions available to every template.
+var Funcs = template.FuncMap{"Title": strings.Title, "doc": Doc}
+
+// Doc renders text as a Go comment wrapped at 80 columns, for doc comments
+// built from user input such as a module's --description.
+func Doc(text string) string {
+	var lines []string
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line) > len("//") && len(line)+1+len(word) > 80 {
+			lines = append(lines, line)
+			line = "//"
+		}
+		line += " " + word
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+// Registry maps a template name to its source. Names ending in ".go" must
+// render to valid Go source; Validate checks this for every entry.
+var Registry = map[string]string{
+	"go.mod":                      GoModTmpl,
+	".gitignore":                  GitignoreTmpl,
+	"internal_main.go":            InternalMainTmpl,
+	"app_main.go":                 AppMainTmpl,
+	"module.go":                   ModuleTmpl,
+	"service.go":                  ServiceTmpl,
+	"controller.go":               ControllerTmpl,
+	"dependencies.go":             DependenciesTmpl,
+	"app_integration_test.go":     AppTestTmpl,
+	"errors.go":                   ErrorsTmpl,
+	"errors_middleware.go":        ErrorsMiddlewareTmpl,
+	"metrics.go":                  MetricsTmpl,
+	"worker_main.go":              WorkerMainTmpl,
+	"worker_consumer_nats.go":     NatsConsumerTmpl,
+	"worker_consumer_kafka.go":    KafkaConsumerTmpl,
+	"worker_consumer_rabbitmq.go": RabbitMQConsumerTmpl,
+	"response.go":                 ResponseTmpl,
+	"cache_store.go":              CacheStoreTmpl,
+	"cache_redis.go":              CacheRedisTmpl,
+	"service_interface.go":        ServiceInterfaceTmpl,
+	"cached_service.go":           CachedServiceTmpl,
+	".grobrc":                     GrobrcTmpl,
+	"binary_main.go":              BinaryMainTmpl,
+	"auth.go":                     AuthTmpl,
+	"auth_middleware.go":          AuthMiddlewareTmpl,
+	"auth_handler.go":             AuthHandlerTmpl,
+	"schema.graphqls":             GraphQLSchemaTmpl,
+	"gqlgen.yml":                  GqlgenConfigTmpl,
+	"graph_resolver.go":           GraphQLResolverTmpl,
+	"graph_handler.go":            GraphQLHandlerTmpl,
+	"reqctx.go":                   ReqCtxTmpl,
+	"reqctx_middleware.go":        ReqCtxMiddlewareTmpl,
+	"queue.go":                    QueueTmpl,
+	"queue_redis.go":              QueueRedisTmpl,
+	"queue_amqp.go":               QueueAMQPTmpl,
+	"queue_module.go":             QueueModuleTmpl,
+	"Dockerfile":                  DockerfileTmpl,
+	".dockerignore":               DockerignoreTmpl,
+	"ci.yml":                      CIGitHubTmpl,
+	".gitlab-ci.yml":              CIGitLabTmpl,
+	"events.go":                   EventSourceTmpl,
+	"sse.go":                      SSEControllerTmpl,
+	"core.go":                     CoreTmpl,
+	"hub.go":                      WebSocketHubTmpl,
+	"websocket.go":                WebSocketControllerTmpl,
+	"enum.go":                     EnumTmpl,
+	"framework_probe.go":          FrameworkProbeTmpl,
+	"respcache.go":                ResponseCacheTmpl,
+	"respcache_store.go":          ResponseCacheStoreTmpl,
+	"model.go":                    ModelTmpl,
+	"ratelimit.go":                RateLimitTmpl,
+	"ratelimit_memory.go":         RateLimitMemoryTmpl,
+	"ratelimit_redis.go":          RateLimitRedisTmpl,
+	"flags.go":                    FeatureFlagsTmpl,
+	"api_client.go":               APIClientTmpl,
+	"api_client_service.go":       APIClientServiceTmpl,
+	"api_client_controller.go":    APIClientControllerTmpl,
+	"gateway.proto":               GrpcProtoTmpl,
+	"gateway.yaml":                GrpcGatewayConfigTmpl,
+	"pb_doc.go":                   GrpcPbDocTmpl,
+	"gateway.go":                  GrpcGatewayTmpl,
+	"sql_repository.go":           SQLRepositoryTmpl,
+	"job_main.go":                 JobMainTmpl,
+	"admin.go":                    AdminControllerTmpl,
+	"admin_list.tmpl":             AdminListPageTmpl,
+	"admin_form.tmpl":             AdminFormPageTmpl,
+	"admin_detail.tmpl":           AdminDetailPageTmpl,
+	"README.md":                   ReadmeTmpl,
+	"pprof.go":                    PprofTmpl,
+	"dto.proto":                   ProtoDTOTmpl,
+	"pb.go":                       PbPackageTmpl,
+	"health.go":                   HealthTmpl,
+	"mapper.go":                   MapperTmpl,
+	"outbox.go":                   OutboxTmpl,
+	"outbox_relay.go":             OutboxRelayTmpl,
+	"outbox_module.go":            OutboxModuleTmpl,
+	"outbox.up.sql":               OutboxMigrationUpTmpl,
+	"outbox.down.sql":             OutboxMigrationDownTmpl,
+	"outbox_relay_main.go":        OutboxRelayMainTmpl,
+	"outbox_publisher.go":         OutboxPublisherTmpl,
+	"module.proto":                ModuleProtoTmpl,
+	"grpc_server.go":              GRPCServerTmpl,
+	"env_config.go":               EnvConfigTmpl,
+	"env_config_load.go":          EnvConfigLoadTmpl,
+	"env_config_module.go":        EnvConfigModuleTmpl,
+	"tracing.go":                  TracingTmpl,
+	"ports.go":                    ModulePortsTmpl,
+	"module_adapter.go":           ModuleAdapterTmpl,
+	"idempotency.go":              IdempotencyTmpl,
+	"idempotency_store.go":        IdempotencyStoreTmpl,
+	"subpackage_module.go":        SubpackageModuleTmpl,
+	"subpackage_handler.go":       SubpackageHandlerTmpl,
+	"subpackage_service.go":       SubpackageServiceTmpl,
+	"subpackage_repository.go":    SubpackageRepositoryTmpl,
+	"batch.go":                    BatchTmpl,
+	"retryclient.go":              RetryClientTmpl,
+	"retryclient_module.go":       RetryClientModuleTmpl,
+	"webhook.go":                  WebhookTmpl,
+	"deadletter.go":               DeadLetterTmpl,
+	"deadletter_sql.go":           DeadLetterSQLTmpl,
+	"deadletter_redis.go":         DeadLetterRedisTmpl,
+	"deadletter_http.go":          DeadLetterHTTPTmpl,
+	"deadletter_up.sql":           DeadLetterMigrationUpTmpl,
+	"deadletter_down.sql":         DeadLetterMigrationDownTmpl,
+	"worker_deadletter.go":        WorkerDeadLetterTmpl,
+	"tenant.go":                   TenantTmpl,
+	"tenant_middleware.go":        TenantMiddlewareTmpl,
+	"tenant_repository.go":        TenantRepositoryTmpl,
+	"table_dto.go":                TableDTOTmpl,
+	"table_crud.go":               TableCRUDTmpl,
+	"fixtures.go":                 FixturesTmpl,
+	"fixture.yaml":                FixtureFileTmpl,
+	"fixtures_helper_test.go":     FixturesHelperTmpl,
+	"api.md":                      APIDocsTmpl,
+	"breaker.go":                  BreakerTmpl,
+	"module.breaker.go":           ModuleBreakerTmpl,
+	"grpcserver.go":               GRPCServerPkgTmpl,
+	"storage.go":                  StorageTmpl,
+	"storage_local.go":            StorageLocalTmpl,
+	"storage_s3.go":               StorageS3Tmpl,
+	"storage_module.go":           StorageModuleTmpl,
+	"upload_controller.go":        UploadControllerTmpl,
+	"go.work":                     GoWorkTmpl,
+	"app.go.mod":                  AppGoModTmpl,
+	"pubsub.go":                   PubSubTmpl,
+	"pubsub_module.go":            PubSubModuleTmpl,
+	"pubsub_example_test.go":      PubSubExampleTmpl,
+	"module.subscriber.go":        ModuleSubscriberTmpl,
+	"seed.go":                     SeedTmpl,
+	"seed_main.go":                SeedMainTmpl,
+	"apiversion.go":               APIVersionTmpl,
+	"module_version.go":           ModuleVersionTmpl,
+	"database.go":                 DatabaseTmpl,
+	"service_impls.go":            ServiceImplsTmpl,
+	"service_impl.go":             ServiceImplTmpl,
+	"admin_auth.go":               AdminAuthTmpl,
+	"admin_login.tmpl":            AdminLoginPageTmpl,
+	"admin_route.go":              AdminRouteTmpl,
+	"module_test.go":              ModuleTestTmpl,
+	"fixtures_test.go":            FixturesTestTmpl,
+	"access_log.go":               AccessLogTmpl,
+	"saga.go":                     SagaTmpl,
+	"saga_store.go":               SagaStoreTmpl,
+	"saga.up.sql":                 SagaMigrationUpTmpl,
+	"saga.down.sql":               SagaMigrationDownTmpl,
+	"sagas_module.go":             SagasModuleTmpl,
+	"saga_definition.go":          SagaDefinitionTmpl,
+	"saga_step.fragment":          SagaStepTmpl,
+	"scheduler_main.go":           SchedulerMainTmpl,
+	"scheduler_registry.go":       SchedulerRegistryTmpl,
+	"scheduler_example_job.go":    SchedulerExampleJobTmpl,
+	"cli_main.go":                 CLIMainTmpl,
+	"compression.go":              CompressionTmpl,
+}
+
+// parsed holds every registered template, parsed once at startup so that a
+// broken template fails fast instead of midway through generating a project.
+var parsed = map[string]*template.Template{}
+
+func init() {
+	for name, src := range Registry {
+		parsed[name] = template.Must(template.New(name).Funcs(Funcs).Parse(src))
+	}
+}
+
+// sampleData returns representative data sets covering the template variants.
+func sampleData() []map[string]string {
+	base := map[string]string{
+		"ProjectName":                   "example.com/shop",
+		"ImportPrefix":                  "example.com/shop",
+		"AppName":                       "api",
+		"ModuleName":                    "users",
+		"ResponseFormat":                "raw",
+		"Queue":                         "nats",
+		"Endpoints":                     `"/healthz"`,
+		"ServiceImports":                "",
+		"ServiceFields":                 "",
+		"ServiceParams":                 "",
+		"ServiceAssigns":                "",
+		"DependencyConstructors":        "",
+		"InterfaceImports":              "",
+		"InterfaceMethods":              "\tExampleMethod() string",
+		"CacheImports":                  "",
+		"CacheStore":                    "memory",
+		"CacheTTL":                      "5 * time.Minute",
+		"CachedMethods":                 "",
+		"Layout":                        "binaries",
+		"EnvPrefix":                     "API",
+		"ReadTimeout":                   "15 * time.Second",
+		"WriteTimeout":                  "15 * time.Second",
+		"IdleTimeout":                   "60 * time.Second",
+		"QueueBackend":                  "redis",
+		"GoVersion":                     "1.19",
+		"BuildPath":                     "internal",
+		"BinaryName":                    "shop",
+		"FrameworkReplace":              "",
+		"EnumName":                      "role",
+		"EnumType":                      "Role",
+		"EnumKind":                      "int",
+		"EnumStringer":                  "",
+		"EnumConsts":                    "\tRoleAdmin Role = iota + 1\n\tRoleViewer",
+		"EnumValues":                    "RoleAdmin, RoleViewer",
+		"EnumCases":                     "\tcase RoleAdmin:\n\t\treturn \"Admin\"\n\tcase RoleViewer:\n\t\treturn \"Viewer\"",
+		"EnumZero":                      "0",
+		"FrameworkVersion":              "v0.1.0",
+		"StructTags":                    "snake",
+		"ModelName":                     "User",
+		"ModelImports":                  "",
+		"ModelFields":                   "\tID int `json:\"id\"`",
+		"RateStrategy":                  "token-bucket",
+		"RateRPS":                       "100",
+		"RateBurst":                     "200",
+		"RateKeyFunc":                   "ByIP",
+		"ModuleType":                    "Users",
+		"ClientEnvPrefix":               "API_PAYMENTS",
+		"ClientBaseURL":                 "https://api.example.com",
+		"GrpcService":                   "ShopService",
+		"GatewayPrefix":                 "/v1",
+		"GrpcAddr":                      "localhost:9090",
+		"RepoTable":                     "users",
+		"RepoDriver":                    "postgres",
+		"RepoIDField":                   "ID",
+		"RepoIDColumn":                  "id",
+		"RepoIDType":                    "int",
+		"RepoAutoID":                    "true",
+		"RepoColumns":                   "id, email",
+		"RepoScanArgs":                  "&m.ID, &m.Email",
+		"RepoIDPlaceholder":             "$1",
+		"RepoInsertColumns":             "email",
+		"RepoInsertValues":              "$1",
+		"RepoInsertArgs":                "m.Email",
+		"RepoUpdateSet":                 "email = $1",
+		"RepoUpdateIDPlaceholder":       "$2",
+		"RepoUpdateArgs":                "m.Email, m.ID",
+		"JobInterval":                   "1 * time.Minute",
+		"JobIntervalText":               "1m0s",
+		"AdminDir":                      "user",
+		"AdminPath":                     "/admin/users",
+		"AdminImports":                  "\t\"strconv\"",
+		"AdminIDField":                  "ID",
+		"AdminParseID":                  "\tn, err := strconv.ParseInt(ctx.Param(\"id\"), 10, 64)\n\tif err != nil {\n\t\treturn nil, false\n\t}\n\tid := int(n)",
+		"AdminListHeaders":              "<th>ID</th>",
+		"AdminListCells":                "<td>{{.ID}}</td>",
+		"AdminColumnCount":              "2",
+		"AdminDetailRows":               "<dt>ID</dt><dd>{{.Item.ID}}</dd>",
+		"AdminFormInputs":               "",
+		"AdminBindFields":               "\titem.Email = ctx.PostForm(\"email\")",
+		"ProjectTitle":                  "shop",
+		"PprofLocalOnly":                "true",
+		"MessageName":                   "UserProfile",
+		"ProtoImports":                  "import \"google/protobuf/timestamp.proto\";",
+		"ProtoFields":                   "  string email = 1;\n  google.protobuf.Timestamp created_at = 2;",
+		"HealthDeps":                    "db,redis",
+		"HealthDB":                      "true",
+		"HealthRedis":                   "true",
+		"MapperToFields":                "\t\tID: m.ID,",
+		"MapperFromFields":              "\t\t// TODO: set ID; UserRequest has no ID field.",
+		"GoPrivate":                     "",
+		"OutboxTable":                   "outbox",
+		"OutboxDriver":                  "postgres",
+		"OutboxDriverName":              "pgx",
+		"OutboxMigration":               "20240101000000_create_outbox",
+		"OutboxInterval":                "time.Second",
+		"OutboxIntervalText":            "1s",
+		"OutboxQueue":                   "",
+		"Transport":                     "http",
+		"ConfigFields":                  "\tDatabaseURL string `env:\"DATABASE_URL\" required:\"true\"`\n\tTimeout time.Duration `env:\"TIMEOUT\" envDefault:\"5s\"`",
+		"ConfigImports":                 "\t\"time\"",
+		"ServiceCtx":                    "",
+		"InterfaceOnly":                 "",
+		"IdempotencyStore":              "memory",
+		"IdempotencyTTL":                "24 * time.Hour",
+		"ModuleImportPath":              "github.com/acme/shop/internal/api/users",
+		"BatchMax":                      "100",
+		"BatchMaxBytes":                 "1 << 20",
+		"Author":                        "Acme Inc.",
+		"License":                       "MIT",
+		"RetryClient":                   "",
+		"RetryMaxRetries":               "3",
+		"RetryBaseBackoff":              "200 * time.Millisecond",
+		"RetryMaxBackoff":               "30 * time.Second",
+		"RetryTimeout":                  "30 * time.Second",
+		"WebhookHeader":                 "Stripe-Signature",
+		"WebhookHash":                   "sha256",
+		"WebhookHashName":               "SHA256",
+		"WebhookEncoding":               "hex",
+		"WebhookRoute":                  "/webhook",
+		"WebhookMaxBytes":               "1048576",
+		"WebhookSecretEnv":              "API_STRIPE_WEBHOOK_SECRET",
+		"WebhookCached":                 "",
+		"ModuleDescription":             "",
+		"DeadLetterStore":               "sql",
+		"DeadLetterDriver":              "postgres",
+		"DeadLetterDriverName":          "pgx",
+		"DeadLetterTable":               "dead_letters",
+		"DeadLetterMaxAttempts":         "5",
+		"DeadLetterBackoff":             "1 * time.Second",
+		"DeadLetterMaxBackoff":          "30 * time.Second",
+		"TenantStrategy":                "header",
+		"TenantHeader":                  "X-Tenant-ID",
+		"TenantDomain":                  "",
+		"TenantField":                   "TenantID",
+		"TenantColumn":                  "tenant_id",
+		"TenantSoftDelete":              "",
+		"TenantP1":                      "$1",
+		"TenantP2":                      "$2",
+		"TenantUpdateSet":               "email = $1",
+		"TenantUpdateArgs":              "m.Email, m.ID",
+		"TenantUpdateIDPlaceholder":     "$2",
+		"TenantUpdateTenantPlaceholder": "$3",
+		"CRUDFields":                    "\tEmail string `json:\"email\" binding:\"required\"`",
+		"CRUDApply":                     "\tm.Email = r.Email",
+		"CRUDImports":                   "",
+		"CRUDSoftDelete":                "",
+		"CRUDControllerImports":         "\t\"strconv\"",
+		"CRUDParseID":                   "\tn, err := strconv.ParseInt(ctx.Param(\"id\"), 10, 64)\n\tif err != nil {\n\t\tc.fail(ctx, ErrUserNotFound)\n\t\treturn 0, false\n\t}\n\tid := int(n)",
+		"FixtureTable":                  "profiles",
+		"FixtureRows":                   "  - name: \"name 1\"",
+		"FixtureNote":                   "",
+		"FixtureDialect":                "Postgres",
+		"APIDocsRoutes":                 "\n## api\n\n### users\n\n| Method | Path | Handler |\n| --- | --- | --- |\n| GET | `/` | `UsersController.GetExample` |\n",
+		"BreakerClient":                 "",
+		"BreakerFailureThreshold":       "5",
+		"BreakerOpenTimeout":            "30 * time.Second",
+		"BreakerHalfOpenMaxCalls":       "1",
+		"Port":                          "8081",
+		"GRPCPort":                      "",
+		"GRPCServerGroup":               "",
+		"StorageBackend":                "local",
+		"UploadMaxBytes":                "100 << 20",
+		"UploadRoute":                   "/uploads",
+		"UploadContentTypes":            "\t\"image/jpeg\": true,\n\t\"image/png\": true,",
+		"Workspace":                     "",
+		"LocalModuleVersion":            "v0.0.0-00010101000000-000000000000",
+		"RepoTableSQL":                  "users",
+		"FixtureTableYAML":              "profiles",
+		"SeedTime":                      "true",
+		"SeedSkipped":                   "Meta",
+		"SeedFields":                    "\t\tEmail: f.Email(),\n\t\tCreatedAt: f.DateRange(now.AddDate(-1, 0, 0), now),",
+		"SeedCommand":                   "seed",
+		"SeedPath":                      "internal/api/users/seed/user",
+		"SeedCount":                     "50",
+		"SeedDriverName":                "pgx",
+		"ModuleImportName":              "users",
+		"APIVersion":                    "v2",
+		"APIVersionPath":                "/users",
+		"DBDriver":                      "postgres",
+		"DBDriverName":                  "pgx",
+		"DBMaxOpen":                     "25",
+		"DBMaxIdle":                     "10",
+		"DBMaxLifetime":                 "30 * time.Minute",
+		"DBMaxLifetimeText":             "30m0s",
+		"ServiceImpls":                  "",
+		"ServiceImplTag":                "fake",
+		"ImplName":                      "real",
+		"ImplType":                      "realUsersService",
+		"ImplBuildTag":                  "!fake",
+		"ImplDefault":                   "true",
+		"AdminAuthMode":                 "basic",
+		"AdminSessionTTL":               "12 * time.Hour",
+		"AdminRoutePath":                "/users",
+		"TestFramework":                 "stdlib",
+		"FixtureDriverName":             "pgx",
+		"FixtureTableSQL":               "profiles",
+		"AccessLogger":                  "slog",
+		"AccessLogLevel":                "info",
+		"AccessLogBodyLimit":            "0",
+		"HealthPort":                    "",
+		"SagaTable":                     "sagas",
+		"SagaDriver":                    "postgres",
+		"SagaMigration":                 "20240101000000_create_sagas",
+		"SagaStaleAfter":                "5 * time.Minute",
+		"SagaStaleAfterText":            "5m0s",
+		"SagaRecover":                   "time.Minute",
+		"SagaRecoverText":               "1m0s",
+		"SagaType":                      "Checkout",
+		"SagaName":                      "checkout",
+		"SagaStepNames":                 "reserve-stock and charge-payment",
+		"SagaStepValues":                "\t\t&checkoutReserveStockStep{},\n\t\t&checkoutChargePaymentStep{},",
+		"SagaSteps":                     "",
+		"StepType":                      "checkoutReserveStockStep",
+		"StepName":                      "reserve-stock",
+		"CLIStandalone":                 "",
+		"CompressionAlgo":               "gzip",
+		"CompressionMinSize":            "1024",
+		"BatchCached":                   "",
+	}
+
+	envelope := copyData(base)
+	envelope["ResponseFormat"] = "envelope"
+
+	withDeps := copyData(base)
+	withDeps["ServiceImports"] = `	"database/sql"`
+	withDeps["ServiceFields"] = "\tsqlDB *sql.DB"
+	withDeps["ServiceParams"] = "sqlDB *sql.DB"
+	withDeps["ServiceAssigns"] = "sqlDB: sqlDB"
+	withDeps["DependencyConstructors"] = "\nfunc newSqlDB() (*sql.DB, error) {\n\treturn nil, errors.New(\"todo\")\n}\n"
+
+	redisCache := copyData(base)
+	redisCache["CacheStore"] = "redis"
+	redisCache["IdempotencyStore"] = "redis"
+	redisCache["DeadLetterStore"] = "redis"
+	redisCache["CacheImports"] = `	"github.com/redis/go-redis/v9"`
+
+	frameworkReplace := copyData(base)
+	frameworkReplace["FrameworkReplace"] = "../grob-framework"
+	frameworkReplace["Workspace"] = "true"
+
+	amqpQueue := copyData(base)
+	amqpQueue["QueueBackend"] = "amqp"
+	amqpQueue["StorageBackend"] = "s3"
+
+	stringEnum := copyData(base)
+	stringEnum["EnumKind"] = "string"
+	stringEnum["EnumConsts"] = "\tRoleAdmin Role = \"admin\"\n\tRoleViewer Role = \"viewer\""
+	stringEnum["EnumZero"] = `""`
+
+	stringerEnum := copyData(base)
+	stringerEnum["EnumStringer"] = "true"
+
+	modelWithImports := copyData(base)
+	modelWithImports["ModelImports"] = `	"time"`
+	modelWithImports["ModelFields"] = "\tCreatedAt time.Time `json:\"created_at\"`"
+
+	fixedWindow := copyData(base)
+	fixedWindow["RateStrategy"] = "fixed-window"
+
+	mysqlRepository := copyData(base)
+	mysqlRepository["RepoDriver"] = "mysql"
+	mysqlRepository["RepoIDPlaceholder"] = "?"
+	mysqlRepository["RepoInsertValues"] = "?"
+	mysqlRepository["RepoUpdateSet"] = "email = ?"
+	mysqlRepository["RepoUpdateIDPlaceholder"] = "?"
+	mysqlRepository["TenantP1"] = "?"
+	mysqlRepository["TenantP2"] = "?"
+	mysqlRepository["TenantUpdateSet"] = "email = ?"
+	mysqlRepository["TenantUpdateIDPlaceholder"] = "?"
+	mysqlRepository["TenantUpdateTenantPlaceholder"] = "?"
+	mysqlRepository["TenantStrategy"] = "subdomain"
+	mysqlRepository["TenantDomain"] = "example.com"
+	mysqlRepository["TenantSoftDelete"] = "deleted_at"
+
+	manualIDRepository := copyData(base)
+	manualIDRepository["RepoAutoID"] = ""
+	manualIDRepository["RepoIDType"] = "string"
+	manualIDRepository["CRUDControllerImports"] = ""
+	manualIDRepository["CRUDParseID"] = "\tid := ctx.Param(\"id\")"
+	manualIDRepository["CRUDSoftDelete"] = "true"
+	manualIDRepository["CRUDImports"] = "\t\"time\""
+	manualIDRepository["CRUDFields"] = "\tEmail string `json:\"email\"`\n\tBornAt *time.Time `json:\"born_at\"`"
+	manualIDRepository["CRUDApply"] = "\tm.Email = r.Email\n\tm.BornAt = r.BornAt"
+	manualIDRepository["TenantStrategy"] = "subdomain"
+
+	pprofPublic := copyData(base)
+	pprofPublic["PprofLocalOnly"] = ""
+
+	healthNoDeps := copyData(base)
+	healthNoDeps["HealthDeps"] = ""
+	healthNoDeps["HealthDB"] = ""
+	healthNoDeps["HealthRedis"] = ""
+
+	privateRepos := copyData(base)
+	privateRepos["GoPrivate"] = "github.com/acme/*"
+
+	mysqlOutbox := copyData(base)
+	mysqlOutbox["OutboxDriver"] = "mysql"
+	mysqlOutbox["OutboxDriverName"] = "mysql"
+	mysqlOutbox["OutboxQueue"] = "true"
+	mysqlOutbox["DeadLetterDriver"] = "mysql"
+	mysqlOutbox["DeadLetterDriverName"] = "mysql"
+	mysqlOutbox["SagaDriver"] = "mysql"
+
+	grpcTransport := copyData(base)
+	grpcTransport["Transport"] = "grpc"
+	grpcTransport["GRPCPort"] = "9090"
+	grpcTransport["GRPCServerGroup"] = "true"
+
+	healthPort := copyData(base)
+	healthPort["HealthPort"] = "9091"
+	// Health checks are on the management port, so app tests have no endpoint.
+	healthPort["Endpoints"] = ""
+
+	grpcHealthPort := copyData(grpcTransport)
+	grpcHealthPort["HealthPort"] = "9091"
+
+	withCtx := copyData(withDeps)
+	withCtx["ServiceCtx"] = "true"
+	withCtx["Transport"] = "both"
+
+	interfaceOnly := copyData(withCtx)
+	interfaceOnly["InterfaceOnly"] = "true"
+	interfaceOnly["ModuleDescription"] = "handles login, logout, and token refresh for the users of every app in the project, which is long enough to wrap"
+
+	serviceImpls := copyData(withCtx)
+	serviceImpls["ServiceImpls"] = "real (default) and fake"
+	serviceImpls["ImplName"] = "fake"
+	serviceImpls["ImplType"] = "fakeUsersService"
+	serviceImpls["ImplBuildTag"] = "fake"
+	serviceImpls["ImplDefault"] = ""
+
+	sessionAdmin := copyData(base)
+	sessionAdmin["AdminAuthMode"] = "session"
+
+	testify := copyData(serviceImpls)
+	testify["TestFramework"] = "testify"
+	testify["FixtureDialect"] = "MySQL"
+
+	zapAccessLog := copyData(base)
+	zapAccessLog["AccessLogger"] = "zap"
+	zapAccessLog["AccessLogBodyLimit"] = "4096"
+
+	standaloneCLI := copyData(base)
+	standaloneCLI["CLIStandalone"] = "true"
+	standaloneCLI["CompressionAlgo"] = "brotli"
+
+	retryClient := copyData(base)
+	retryClient["RetryClient"] = "true"
+	retryClient["BreakerClient"] = "true"
+	retryClient["ModuleDescription"] = "handles login, logout, and token refresh"
+
+	base64Webhook := copyData(envelope)
+	base64Webhook["WebhookHash"] = "sha1"
+	base64Webhook["WebhookEncoding"] = "base64"
+	base64Webhook["WebhookCached"] = "true"
+	base64Webhook["BatchCached"] = "true"
+
+	return []map[string]string{base, envelope, withDeps, redisCache, amqpQueue, frameworkReplace, stringEnum, stringerEnum, modelWithImports, fixedWindow, mysqlRepository, manualIDRepository, pprofPublic, healthNoDeps, privateRepos, mysqlOutbox, grpcTransport, healthPort, grpcHealthPort, withCtx, interfaceOnly, serviceImpls, sessionAdmin, testify, zapAccessLog, retryClient, base64Webhook, standaloneCLI}
+}
+
+func copyData(data map[string]string) map[string]string {
+	c := make(map[string]string, len(data))
+	for k, v := range data {
+		c[k] = v
+	}
+	return c
+}
+
+// Validate renders every registered template with representative data and
+// checks that the Go templates produce syntactically valid source.
+func Validate() error {
+	names := make([]string, 0, len(parsed))
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, name := range names {
+		// Fail on keys missing from the sample data instead of rendering "<no value>".
+		strict := template.Must(parsed[name].Clone()).Option("missingkey=error")
+		for i, data := range sampleData() {
+			var buf bytes.Buffer
+			if err := strict.Execute(&buf, data); err != nil {
+				errs = append(errs, fmt.Sprintf("%s (sample %d): %v", name, i+1, err))
+				continue
+			}
+			if !strings.HasSuffix(name, ".go") {
+				continue
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), name, buf.Bytes(), parser.AllErrors); err != nil {
+				errs = append(errs, fmt.Sprintf("%s (sample %d): %v", name, i+1, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid templates:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package templates
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplatesFormat(t *testing.T) {
+	for name := range Registry {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			tmpl := template.Must(parsed[name].Clone()).Option("missingkey=error")
+			for i, data := range sampleData() {
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, data); err != nil {
+					t.Fatalf("sample %d: %v", i+1, err)
+				}
+				if _, err := format.Source(buf.Bytes()); err != nil {
+					t.Errorf("sample %d: %v", i+1, err)
+				}
+			}
+		})
+	}
+}
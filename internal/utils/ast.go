@@ -2,112 +2,1055 @@ package utils
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
 	"os"
+	"strconv"
 	"strings"
 )
 
-// AddAppToInternalMain uses AST parsing to add a new app to internal/main.go
+// ErrAppsMapNotFound is returned by AddAppToInternalMain when internal/main.go no
+// longer contains the map[string]AppRunner literal that apps are registered in.
+var ErrAppsMapNotFound = errors.New("could not find the apps map")
+
+// AddAppToInternalMain uses AST parsing to add a new app to internal/main.go.
+// The entry goes into the apps map in order of app name, so the map reads the
+// same however the apps were added. The file is edited as text at the positions
+// found in the AST, so comments and formatting elsewhere in the file are preserved.
 func AddAppToInternalMain(path, projectName, appName string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if src, err = addImportSource(path, src, "", fmt.Sprintf("%s/internal/%s", projectName, appName)); err != nil {
+		return err
+	}
+
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
 	if err != nil {
 		return err
 	}
 
+	var apps *ast.CompositeLit
 	ast.Inspect(node, func(n ast.Node) bool {
-		if gd, ok := n.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
-			newImport := &ast.ImportSpec{
-				Path: &ast.BasicLit{
-					Kind:  token.STRING,
-					Value: fmt.Sprintf(`"%s/internal/%s"`, projectName, appName),
-				},
-			}
-			gd.Specs = append(gd.Specs, newImport)
-			return false
+		if cl, ok := n.(*ast.CompositeLit); ok && apps == nil {
+			if kv, ok := cl.Type.(*ast.MapType); ok {
+				if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == "string" {
+					apps = cl
+					return false
+				}
+			}
 		}
-		return true
+		return apps == nil
+	})
+	if apps == nil {
+		return fmt.Errorf("%w in %s", ErrAppsMapNotFound, path)
+	}
+
+	entry := fmt.Sprintf("%q: %s.App{}", appName, appName)
+	out, err := insertListElement(fset, src, apps.Elts, apps.Lbrace, apps.Rbrace, entry, appName, func(e ast.Expr) string {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			if lit, ok := kv.Key.(*ast.BasicLit); ok {
+				name, _ := strconv.Unquote(lit.Value)
+				return name
+			}
+		}
+		return ""
 	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, FileMode)
+}
+
+// appSelection runs only the app named by the first argument of the project's
+// binary. It is part of InternalMainTmpl, and AddAppSelectionToInternalMain
+// adds it to older files.
+const appSelection = `// An app named by the first argument runs alone, e.g. a command-line
+// app: go run ./internal importer --help
+if len(os.Args) > 1 {
+	if app, ok := apps[os.Args[1]]; ok {
+		app.Run()
+		return
+	}
+}
+
+`
+
+// AddAppSelectionToInternalMain makes internal/main.go run only the app named
+// by its first argument, if any, as command-line apps need. The check goes
+// right before the loop starting the apps. It reports false if the file has no
+// such loop over the apps map, and leaves a file that selects apps unchanged.
+func AddAppSelectionToInternalMain(path string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Contains(src, []byte("apps[os.Args[1]]")) {
+		return true, nil
+	}
+	if src, err = addImportSource(path, src, "", "os"); err != nil {
+		return false, err
+	}
 
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+	var loop *ast.RangeStmt
 	ast.Inspect(node, func(n ast.Node) bool {
-		if cl, ok := n.(*ast.CompositeLit); ok {
-			if kv, ok := cl.Type.(*ast.MapType); ok {
-				if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == "string" {
-					newAppEntry := &ast.KeyValueExpr{
-						Key: &ast.BasicLit{
-							Kind:  token.STRING,
-							Value: fmt.Sprintf(`"%s"`, appName),
-						},
-						Value: &ast.CompositeLit{
-							Type: &ast.SelectorExpr{
-								X:   ast.NewIdent(appName),
-								Sel: ast.NewIdent("App"),
-							},
-						},
-					}
-					cl.Elts = append(cl.Elts, newAppEntry)
+		if rs, ok := n.(*ast.RangeStmt); ok && loop == nil {
+			if ident, ok := rs.X.(*ast.Ident); ok && ident.Name == "apps" {
+				loop = rs
+			}
+		}
+		return loop == nil
+	})
+	if loop == nil {
+		return false, nil
+	}
+
+	out, err := insertSource(src, fset.Position(loop.Pos()).Offset, appSelection)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
+
+// RegisteredApps returns the apps imported by internal/main.go, i.e. the last
+// element of every import path directly under <projectName>/internal/.
+func RegisteredApps(path, projectName string) ([]string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := projectName + "/internal/"
+	var apps []string
+	for _, imp := range node.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		app, ok := strings.CutPrefix(importPath, prefix)
+		if ok && app != "" && !strings.Contains(app, "/") {
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
+}
+
+// AddModuleToAppMain uses AST parsing to add a new module to an app's main file.
+// Like AddAppToInternalMain, it edits the source text so comments stay in place,
+// and keeps the core.New arguments sorted.
+// importPath is the module's import path, as returned by ModuleImportPath, and
+// pkgName and typeName are the names returned by ModuleNames.
+func AddModuleToAppMain(path, importPath, pkgName, typeName string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	importName := ModuleImportName(pkgName)
+	if src, err = addImportSource(path, src, importName, importPath); err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	var newCall *ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok && newCall == nil {
+			if se, ok := ce.Fun.(*ast.SelectorExpr); ok {
+				if x, ok := se.X.(*ast.Ident); ok && x.Name == "core" && se.Sel.Name == "New" {
+					newCall = ce
 					return false
 				}
 			}
 		}
-		return true
+		return newCall == nil
+	})
+	if newCall == nil {
+		return fmt.Errorf("could not find the core.New call in %s", path)
+	}
+
+	entry := fmt.Sprintf("%s.%sModule{}", importName, typeName)
+	out, err := insertListElement(fset, src, newCall.Args, newCall.Lparen, newCall.Rparen, entry, entry, func(e ast.Expr) string {
+		return string(src[fset.Position(e.Pos()).Offset:fset.Position(e.End()).Offset])
 	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, FileMode)
+}
+
+// insertListElement inserts elem into a comma-separated list (composite literal
+// elements or call arguments) delimited by the given positions, ahead of the
+// first element whose sort key is greater than key, so a sorted list stays
+// sorted. A list written one element per line keeps that layout.
+func insertListElement(fset *token.FileSet, src []byte, elems []ast.Expr, opening, closing token.Pos, elem, key string, sortKey func(ast.Expr) string) ([]byte, error) {
+	closeOff := fset.Position(closing).Offset
+	if len(elems) == 0 {
+		return insertSource(src, closeOff, elem)
+	}
+
+	openOff := fset.Position(opening).Offset + 1
+	multiLine := bytes.Contains(src[openOff:fset.Position(elems[0].Pos()).Offset], []byte("\n"))
+	for i, e := range elems {
+		if sortKey(e) <= key {
+			continue
+		}
+		start := fset.Position(e.Pos()).Offset
+		if !multiLine {
+			return insertSource(src, start, elem+", ")
+		}
+		// Insert on its own line above the element and any comment over it.
+		prevEnd := openOff
+		if i > 0 {
+			prevEnd = fset.Position(elems[i-1].End()).Offset
+		}
+		lineStart := prevEnd + bytes.IndexByte(src[prevEnd:], '\n') + 1
+		indent := start - (bytes.LastIndexByte(src[:start], '\n') + 1)
+		return insertSource(src, lineStart, string(src[start-indent:start])+elem+",\n")
+	}
+
+	lastEnd := fset.Position(elems[len(elems)-1].End()).Offset
+	if bytes.Contains(src[lastEnd:closeOff], []byte(",")) {
+		// Multi-line list with a trailing comma: add the element on its own line.
+		return insertSource(src, closeOff, elem+",\n")
+	}
+	return insertSource(src, lastEnd, ", "+elem)
+}
+
+// AddProviderToModule registers a constructor in a module's Register method.
+// It reports whether the file contained a Register method to edit.
+func AddProviderToModule(path, constructor string) (bool, error) {
+	return addToRegister(path, "Provide", constructor)
+}
+
+// AddDecoratorToModule registers a decorator, a function that takes a value in
+// the container and returns its replacement, in a module's Register method.
+// It reports whether the file contained a Register method to edit.
+func AddDecoratorToModule(path, decorator string) (bool, error) {
+	return addToRegister(path, "Decorate", decorator)
+}
+
+// addToRegister adds a call of the container method, e.g. Provide, with fn to
+// a module's Register method, before its final return.
+func addToRegister(path, method, fn string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+
+	var register *ast.FuncDecl
+	for _, decl := range node.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv != nil && fd.Name.Name == "Register" {
+			register = fd
+			break
+		}
+	}
+	if register == nil || register.Body == nil {
+		return false, nil
+	}
+
+	containerName := "container"
+	if params := register.Type.Params.List; len(params) > 0 && len(params[0].Names) > 0 {
+		containerName = params[0].Names[0].Name
+	}
+	snippet := fmt.Sprintf("if err := %s.%s(%s); err != nil {\nreturn err\n}\n\n", containerName, method, fn)
+
+	// Keep the trailing "return nil" last.
+	insertAt := register.Body.Rbrace
+	if n := len(register.Body.List); n > 0 {
+		if ret, ok := register.Body.List[n-1].(*ast.ReturnStmt); ok {
+			insertAt = ret.Pos()
+		}
+	}
+
+	out, err := insertSource(src, fset.Position(insertAt).Offset, snippet)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
+
+// AddRouteToController adds a route to a controller's RegisterRoutes method,
+// using the method's own receiver and router names: method "POST", route
+// "/batch" and handler "Batch" add router.POST("/batch", c.Batch). It reports
+// whether typeName has a RegisterRoutes method to edit.
+func AddRouteToController(path, typeName, method, route, handler string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+
+	for _, decl := range node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || fd.Body == nil || fd.Name.Name != "RegisterRoutes" || receiverTypeName(fd.Recv) != typeName {
+			continue
+		}
+		params := fd.Type.Params.List
+		if len(fd.Recv.List[0].Names) == 0 || len(params) == 0 || len(params[0].Names) == 0 {
+			return false, fmt.Errorf("%s.RegisterRoutes in %s needs a named receiver and router", typeName, path)
+		}
+		stmt := fmt.Sprintf("%s.%s(%q, %s.%s)", params[0].Names[0].Name, method, route, fd.Recv.List[0].Names[0].Name, handler)
+		if bytes.Contains(src, []byte(stmt)) {
+			return true, nil
+		}
+		out, err := insertSource(src, fset.Position(fd.Body.Rbrace).Offset, stmt+"\n")
+		if err != nil {
+			return false, err
+		}
+		return true, os.WriteFile(path, out, FileMode)
+	}
+	return false, nil
+}
+
+// AddMethodToInterface adds a method, e.g. "Handle(ctx context.Context) error",
+// to the named interface type, importing the given packages for its signature.
+// It reports whether the file declares the interface; an interface that already
+// has a method of that name is left unchanged.
+func AddMethodToInterface(path, typeName, method string, imports ...string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	name := method
+	if i := strings.IndexByte(method, '('); i >= 0 {
+		name = method[:i]
+	}
+	iface, _, err := findInterface(path, src, typeName)
+	if err != nil || iface == nil {
+		return false, err
+	}
+	for _, m := range iface.Methods.List {
+		for _, n := range m.Names {
+			if n.Name == name {
+				return true, nil
+			}
+		}
+	}
+
+	for _, importPath := range imports {
+		if src, err = addImportSource(path, src, "", importPath); err != nil {
+			return false, err
+		}
+	}
+	// The imports moved the interface, so find it again.
+	iface, fset, err := findInterface(path, src, typeName)
+	if err != nil {
+		return false, err
+	}
+	out, err := insertSource(src, fset.Position(iface.Methods.Closing).Offset, method+"\n")
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
+
+// findInterface returns the declaration of the named interface type in a file, or nil.
+func findInterface(path string, src []byte, name string) (*ast.InterfaceType, *token.FileSet, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, decl := range node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.Name == name {
+				return it, fset, nil
+			}
+		}
+	}
+	return nil, fset, nil
+}
+
+// WrapCallArgument wraps an argument of the first call to a method named
+// method in the file: with wrapper "withRetry(ctx, %s)" and arg 1,
+// consumer.Consume(ctx, handle) becomes consumer.Consume(ctx, withRetry(ctx, handle)).
+// It reports whether such a call was found; an argument already wrapped is left alone.
+func WrapCallArgument(path, method string, arg int, wrapper string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+
+	var call *ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok && call == nil {
+			if se, ok := ce.Fun.(*ast.SelectorExpr); ok && se.Sel.Name == method && len(ce.Args) > arg {
+				call = ce
+			}
+		}
+		return call == nil
+	})
+	if call == nil {
+		return false, nil
+	}
+
+	start := fset.Position(call.Args[arg].Pos()).Offset
+	end := fset.Position(call.Args[arg].End()).Offset
+	wrapped := fmt.Sprintf(wrapper, string(src[start:end]))
+	if prefix, _, ok := strings.Cut(wrapper, "("); ok && strings.HasPrefix(string(src[start:end]), prefix+"(") {
+		return true, nil
+	}
+	out := append(append(append([]byte(nil), src[:start]...), wrapped...), src[end:]...)
+	if out, err = format.Source(out); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
+
+// AddCallArgument adds arg, given as source such as "\"v2\"", to the
+// arguments of the first call of pkg.fn in the file, keeping the arguments
+// after the first sorted. It reports whether the call was found; an argument
+// already passed is not added again.
+func AddCallArgument(path, pkg, fn, arg string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+
+	var call *ast.CallExpr
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ce, ok := n.(*ast.CallExpr); ok && call == nil {
+			if se, ok := ce.Fun.(*ast.SelectorExpr); ok && se.Sel.Name == fn {
+				if x, ok := se.X.(*ast.Ident); ok && x.Name == pkg && len(ce.Args) > 0 {
+					call = ce
+				}
+			}
+		}
+		return call == nil
+	})
+	if call == nil {
+		return false, nil
+	}
+
+	source := func(e ast.Expr) string {
+		return string(src[fset.Position(e.Pos()).Offset:fset.Position(e.End()).Offset])
+	}
+	for _, a := range call.Args[1:] {
+		if source(a) == arg {
+			return true, nil
+		}
+	}
+	var out []byte
+	if len(call.Args) == 1 {
+		out, err = insertSource(src, fset.Position(call.Args[0].End()).Offset, ", "+arg)
+	} else {
+		out, err = insertListElement(fset, src, call.Args[1:], call.Args[0].End(), call.Rparen, arg, arg, source)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
+
+// ReplaceInFile replaces the first occurrence of old in a Go file with new and
+// imports the given packages, grouping imports with localPrefix as the local
+// prefix. It reports whether old was found; the file is unchanged if not.
+func ReplaceInFile(path, old, new, localPrefix string, imports ...string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	i := bytes.Index(src, []byte(old))
+	if i < 0 {
+		return false, nil
+	}
+	out := append(append(append([]byte(nil), src[:i]...), new...), src[i+len(old):]...)
+	for _, importPath := range imports {
+		if out, err = addImportSource(path, out, "", importPath); err != nil {
+			return false, err
+		}
+	}
+	if out, err = GroupImports(out, localPrefix); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
 
+// insertSource inserts text into src at the given byte offset and gofmts the result.
+// Editing the source text rather than the AST keeps existing comments where they were.
+func insertSource(src []byte, offset int, text string) ([]byte, error) {
 	var buf bytes.Buffer
-	if err := format.Node(&buf, fset, node); err != nil {
+	buf.Write(src[:offset])
+	buf.WriteString(text)
+	buf.Write(src[offset:])
+	return format.Source(buf.Bytes())
+}
+
+// AddStatementToAppMain adds an import and a statement to an app's main file.
+// A statement registering routes on app.Router() goes after the routes in
+// newApp, right before it returns the app, so integration tests serve the
+// routes too. Other statements, such as defers that must last while the server
+// runs, go right before the server is started. See AddMiddlewareToAppMain for
+// middleware.
+func AddStatementToAppMain(path, importName, importPath, stmt string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(src, []byte(stmt)) {
+		return nil
+	}
+
+	if importPath != "" {
+		if src, err = addImportSource(path, src, importName, importPath); err != nil {
+			return err
+		}
+	}
+
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	var start ast.Stmt
+	if !strings.HasPrefix(stmt, "defer ") && strings.Contains(stmt, "app.Router()") {
+		start = findAppReturn(node)
+	}
+	if start == nil {
+		// Main files from before newApp create the app in Run.
+		start = findServerStart(node)
+	}
+	if start == nil {
+		return fmt.Errorf("could not find where the server is started in %s", path)
+	}
+
+	// Keep a comment describing the server start directly above it.
+	pos := start.Pos()
+	for _, cg := range node.Comments {
+		if cg.End() < pos && fset.Position(cg.End()).Line == fset.Position(pos).Line-1 {
+			pos = cg.Pos()
+		}
+	}
+	out, err := insertSource(src, fset.Position(pos).Offset, stmt+"\n\n")
+	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, buf.Bytes(), 0644)
+	return os.WriteFile(path, out, FileMode)
 }
 
-// AddModuleToAppMain uses AST parsing to add a new module to an app's main file.
-func AddModuleToAppMain(path, projectName, appName, moduleName string) error {
+// AddMiddlewareToAppMain adds an import and a statement registering middleware
+// to an app's main file. gin applies middleware only to routes registered after
+// it, so the statement goes right after the app is created, behind the
+// middleware added before it, and ahead of every route.
+func AddMiddlewareToAppMain(path, importName, importPath, stmt string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(src, []byte(stmt)) {
+		return nil
+	}
+
+	if importPath != "" {
+		if src, err = addImportSource(path, src, importName, importPath); err != nil {
+			return err
+		}
+	}
+
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	after := findMiddlewareEnd(fset, src, node)
+	if after == nil {
+		return fmt.Errorf("could not find where the app is created in %s", path)
+	}
+
+	out, err := insertSource(src, fset.Position(after.End()).Offset, "\n"+stmt)
 	if err != nil {
 		return err
 	}
+	return os.WriteFile(path, out, FileMode)
+}
 
+// findMiddlewareEnd returns the "app := core.New(...)" statement, or the last
+// of the middleware statements directly following it.
+func findMiddlewareEnd(fset *token.FileSet, src []byte, node *ast.File) ast.Stmt {
+	var last ast.Stmt
 	ast.Inspect(node, func(n ast.Node) bool {
-		if gd, ok := n.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
-			newImport := &ast.ImportSpec{
-				Name: ast.NewIdent(moduleName),
-				Path: &ast.BasicLit{
-					Kind:  token.STRING,
-					Value: fmt.Sprintf(`"%s/internal/%s/%s"`, projectName, appName, moduleName),
-				},
-			}
-			gd.Specs = append(gd.Specs, newImport)
+		block, ok := n.(*ast.BlockStmt)
+		if !ok || last != nil {
+			return last == nil
+		}
+		for i, stmt := range block.List {
+			if !isCoreNew(stmt) {
+				continue
+			}
+			last = stmt
+			for _, next := range block.List[i+1:] {
+				text := string(src[fset.Position(next.Pos()).Offset:fset.Position(next.End()).Offset])
+				if !strings.HasPrefix(text, "app.Router().Use(") && !strings.Contains(text, ".Setup(app.Router())") {
+					break
+				}
+				last = next
+			}
 			return false
 		}
 		return true
 	})
+	return last
+}
+
+// isCoreNew reports whether stmt is an "app := core.New(...)" assignment.
+func isCoreNew(stmt ast.Stmt) bool {
+	as, ok := stmt.(*ast.AssignStmt)
+	if !ok || len(as.Rhs) != 1 {
+		return false
+	}
+	ce, ok := as.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	se, ok := ce.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := se.X.(*ast.Ident)
+	return ok && x.Name == "core" && se.Sel.Name == "New"
+}
+
+// findAppReturn returns the "return app" statement of the function creating the
+// app with core.New, such as newApp.
+func findAppReturn(node *ast.File) ast.Stmt {
+	var ret ast.Stmt
+	ast.Inspect(node, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok || ret != nil {
+			return ret == nil
+		}
+		created := false
+		for _, stmt := range block.List {
+			created = created || isCoreNew(stmt)
+			rs, ok := stmt.(*ast.ReturnStmt)
+			if !created || !ok || len(rs.Results) != 1 {
+				continue
+			}
+			if id, ok := rs.Results[0].(*ast.Ident); ok && id.Name == "app" {
+				ret = rs
+				return false
+			}
+		}
+		return true
+	})
+	return ret
+}
 
+// findServerStart returns the statement that starts an app's server: either the
+// "srv := &http.Server{...}" declaration or a legacy "app.Start(port)" call.
+func findServerStart(node *ast.File) ast.Stmt {
+	var start ast.Stmt
 	ast.Inspect(node, func(n ast.Node) bool {
-		if ce, ok := n.(*ast.CallExpr); ok {
-			if se, ok := ce.Fun.(*ast.SelectorExpr); ok {
-				if x, ok := se.X.(*ast.Ident); ok && x.Name == "core" && se.Sel.Name == "New" {
-					newModuleEntry := &ast.CompositeLit{
-						Type: &ast.SelectorExpr{
-							X:   ast.NewIdent(moduleName),
-							Sel: ast.NewIdent(strings.Title(moduleName) + "Module"),
-						},
-					}
-					ce.Args = append(ce.Args, newModuleEntry)
-					return false
+		if start != nil {
+			return false
+		}
+		switch s := n.(type) {
+		case *ast.AssignStmt:
+			if len(s.Rhs) == 1 && isHTTPServerLit(s.Rhs[0]) {
+				start = s
+			}
+		case *ast.ExprStmt:
+			if ce, ok := s.X.(*ast.CallExpr); ok {
+				if se, ok := ce.Fun.(*ast.SelectorExpr); ok && se.Sel.Name == "Start" {
+					start = s
 				}
 			}
 		}
 		return true
 	})
+	return start
+}
+
+// isHTTPServerLit reports whether expr is an &http.Server{...} literal.
+func isHTTPServerLit(expr ast.Expr) bool {
+	ue, ok := expr.(*ast.UnaryExpr)
+	if !ok || ue.Op != token.AND {
+		return false
+	}
+	cl, ok := ue.X.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	se, ok := cl.Type.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := se.X.(*ast.Ident)
+	return ok && x.Name == "http" && se.Sel.Name == "Server"
+}
+
+// addImportSource adds an import to src unless the path is already imported.
+func addImportSource(path string, src []byte, name, importPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := fmt.Sprintf("%q", importPath)
+	for _, imp := range node.Imports {
+		if imp.Path.Value == quoted {
+			return src, nil
+		}
+	}
+
+	spec := quoted
+	if name != "" {
+		spec = name + " " + quoted
+	}
+	for _, decl := range node.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			if gd.Rparen.IsValid() {
+				return insertSource(src, fset.Position(gd.Rparen).Offset, spec+"\n")
+			}
+			// Turn a single-line import into a grouped one.
+			return insertSource(src, fset.Position(gd.End()).Offset, "\nimport "+spec)
+		}
+	}
+	return insertSource(src, fset.Position(node.Name.End()).Offset, "\n\nimport "+spec)
+}
+
+// AppModule is a module registered in an app's core.New call.
+type AppModule struct {
+	// Expr is the source of the registration, e.g. "users.UsersModule{}".
+	Expr string
+	// ImportName and ImportPath identify the package the module comes from.
+	ImportName string
+	ImportPath string
+}
+
+// ParseAppModules returns the modules registered in an app's main file.
+func ParseAppModules(path string) ([]AppModule, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := map[string]string{}
+	for _, imp := range node.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = importPath
+	}
 
+	var modules []AppModule
+	ast.Inspect(node, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		se, ok := ce.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := se.X.(*ast.Ident); !ok || x.Name != "core" || se.Sel.Name != "New" {
+			return true
+		}
+		for _, arg := range ce.Args {
+			m := AppModule{Expr: exprSource(fset, src, node.Comments, arg)}
+			if cl, ok := arg.(*ast.CompositeLit); ok {
+				if sel, ok := cl.Type.(*ast.SelectorExpr); ok {
+					if pkg, ok := sel.X.(*ast.Ident); ok {
+						m.ImportName = pkg.Name
+						m.ImportPath = imports[pkg.Name]
+					}
+				}
+			}
+			modules = append(modules, m)
+		}
+		return false
+	})
+	return modules, nil
+}
+
+// exprSource returns an expression's source on a single line, without comments.
+func exprSource(fset *token.FileSet, src []byte, comments []*ast.CommentGroup, expr ast.Expr) string {
+	start, end := fset.Position(expr.Pos()).Offset, fset.Position(expr.End()).Offset
+
+	var sb strings.Builder
+	pos := start
+	for _, cg := range comments {
+		cs, ce := fset.Position(cg.Pos()).Offset, fset.Position(cg.End()).Offset
+		if cs < start || ce > end {
+			continue
+		}
+		sb.Write(src[pos:cs])
+		pos = ce
+	}
+	sb.Write(src[pos:end])
+
+	text := strings.Join(strings.Fields(sb.String()), " ")
+	parsed, err := parser.ParseExpr(text)
+	if err != nil {
+		return text
+	}
 	var buf bytes.Buffer
-	if err := format.Node(&buf, fset, node); err != nil {
+	if err := format.Node(&buf, token.NewFileSet(), parsed); err != nil {
+		return text
+	}
+	return buf.String()
+}
+
+// FindModuleType returns the type prefix of the module declared in a module
+// file, e.g. "OrderService" for "type OrderServiceModule struct{}", or "" if
+// the file declares no module type.
+func FindModuleType(path string) string {
+	node, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return ""
+	}
+	for _, decl := range node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.StructType); ok && strings.HasSuffix(ts.Name.Name, "Module") && ts.Name.Name != "Module" {
+				return strings.TrimSuffix(ts.Name.Name, "Module")
+			}
+		}
+	}
+	return ""
+}
+
+// RemoveAppFromInternalMain removes an app's entry from the apps map in
+// internal/main.go, along with its import. It is the inverse of AddAppToInternalMain.
+func RemoveAppFromInternalMain(path, projectName, appName string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	ast.Inspect(node, func(n ast.Node) bool {
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok || out != nil {
+			return out == nil
+		}
+		if _, ok := cl.Type.(*ast.MapType); !ok {
+			return true
+		}
+		for i, elt := range cl.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := kv.Key.(*ast.BasicLit); ok && key.Value == strconv.Quote(appName) {
+				out = removeListElement(fset, src, cl.Elts, i)
+				return false
+			}
+		}
+		return true
+	})
+	if out == nil {
+		return fmt.Errorf("app %q is not registered in %s", appName, path)
+	}
+	if out, err = removeImportSource(path, out, projectName+"/internal/"+appName); err != nil {
 		return err
 	}
-	return os.WriteFile(path, buf.Bytes(), 0644)
+	return os.WriteFile(path, out, FileMode)
+}
+
+// RemoveModuleFromAppMain removes the modules imported from importPath from an
+// app's core.New call, and the import itself if nothing else uses it.
+func RemoveModuleFromAppMain(path, importPath string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		name := importNameOf(node, importPath)
+		if name == "" {
+			return fmt.Errorf("%s is not imported in %s", importPath, path)
+		}
+
+		var out []byte
+		ast.Inspect(node, func(n ast.Node) bool {
+			ce, ok := n.(*ast.CallExpr)
+			if !ok || out != nil {
+				return out == nil
+			}
+			se, ok := ce.Fun.(*ast.SelectorExpr)
+			if !ok || se.Sel.Name != "New" {
+				return true
+			}
+			if x, ok := se.X.(*ast.Ident); !ok || x.Name != "core" {
+				return true
+			}
+			for i, arg := range ce.Args {
+				if cl, ok := arg.(*ast.CompositeLit); ok {
+					if sel, ok := cl.Type.(*ast.SelectorExpr); ok {
+						if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == name {
+							out = removeListElement(fset, src, ce.Args, i)
+							return false
+						}
+					}
+				}
+			}
+			return true
+		})
+		if out == nil {
+			break
+		}
+		if src, err = format.Source(out); err != nil {
+			return err
+		}
+	}
+
+	out, err := removeImportSource(path, src, importPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, FileMode)
+}
+
+// removeListElement deletes element i of a comma-separated list together with
+// the comma that separates it from its neighbours.
+func removeListElement(fset *token.FileSet, src []byte, elems []ast.Expr, i int) []byte {
+	start, end := fset.Position(elems[i].Pos()).Offset, fset.Position(elems[i].End()).Offset
+	switch {
+	case i+1 < len(elems):
+		end = fset.Position(elems[i+1].Pos()).Offset
+	case i > 0:
+		start = fset.Position(elems[i-1].End()).Offset
+	default:
+		// The only element: drop a trailing comma as well.
+		rest := bytes.TrimLeft(src[end:], " \t\n")
+		if len(rest) > 0 && rest[0] == ',' {
+			end = len(src) - len(rest) + 1
+		}
+	}
+	out := make([]byte, 0, len(src))
+	out = append(out, src[:start]...)
+	return append(out, src[end:]...)
+}
+
+// removeImportSource removes the import of importPath from src unless the
+// package is still referenced. It formats the result.
+func removeImportSource(path string, src []byte, importPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	name := importNameOf(node, importPath)
+	if name == "" {
+		return format.Source(src)
+	}
+
+	used := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if se, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := se.X.(*ast.Ident); ok && x.Name == name {
+				used = true
+			}
+		}
+		return !used
+	})
+	if used {
+		return format.Source(src)
+	}
+
+	for _, decl := range node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			is := spec.(*ast.ImportSpec)
+			if strings.Trim(is.Path.Value, `"`) != importPath {
+				continue
+			}
+			var start, end token.Pos = is.Pos(), is.End()
+			if !gd.Lparen.IsValid() || len(gd.Specs) == 1 {
+				start, end = gd.Pos(), gd.End()
+			}
+			from, to := fset.Position(start).Offset, fset.Position(end).Offset
+			// Take the whole line when the spec is alone on it, so no blank
+			// line is left inside the import block.
+			lineStart := bytes.LastIndexByte(src[:from], '\n') + 1
+			lineEnd := to + bytes.IndexByte(src[to:], '\n')
+			if lineEnd >= to && len(bytes.TrimSpace(src[lineStart:from])) == 0 && len(bytes.TrimSpace(src[to:lineEnd])) == 0 {
+				from, to = lineStart, lineEnd+1
+			}
+			out := make([]byte, 0, len(src))
+			out = append(out, src[:from]...)
+			out = append(out, src[to:]...)
+			return format.Source(out)
+		}
+	}
+	return format.Source(src)
+}
+
+// importNameOf returns the name a file refers to an imported package by, or
+// "" if the file does not import it.
+func importNameOf(node *ast.File, importPath string) string {
+	for _, imp := range node.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return importPath[strings.LastIndex(importPath, "/")+1:]
+	}
+	return ""
 }
@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestFile writes src to name in a temporary directory and returns its path.
+func writeTestFile(t *testing.T, name, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(src), FileMode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readTestFile returns the contents of path.
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+const testAppMain = `package api
+
+import (
+	"log"
+
+	"example.com/shop/internal/api/core"
+	"example.com/shop/internal/api/users"
+)
+
+func main() {
+	app := core.New(users.UsersModule{})
+	log.Fatal(app.Run())
+}
+`
+
+func TestAddModuleToAppMainAliasesStdlibNames(t *testing.T) {
+	path := writeTestFile(t, "api_main.go", testAppMain)
+	if err := AddModuleToAppMain(path, "example.com/shop/internal/api/http", "http", "HTTP"); err != nil {
+		t.Fatal(err)
+	}
+	got := readTestFile(t, path)
+	for _, want := range []string{
+		`httpmod "example.com/shop/internal/api/http"`,
+		"core.New(httpmod.HTTPModule{}, users.UsersModule{})",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("main file does not contain %s:\n%s", want, got)
+		}
+	}
+}
+
+const testInternalMain = `package main
+
+import (
+	"example.com/shop/internal/orders"
+)
+
+// AppRunner defines the interface for a runnable application.
+type AppRunner interface {
+	Run()
+}
+
+func main() {
+	apps := map[string]AppRunner{
+		// orders must start before the workers that read its queue.
+		"orders": orders.App{},
+	}
+	for _, app := range apps {
+		app.Run()
+	}
+}
+`
+
+func TestAddAppToInternalMainKeepsComments(t *testing.T) {
+	path := writeTestFile(t, "main.go", testInternalMain)
+	if err := AddAppToInternalMain(path, "example.com/shop", "billing"); err != nil {
+		t.Fatal(err)
+	}
+	got := readTestFile(t, path)
+	for _, want := range []string{
+		"// AppRunner defines the interface for a runnable application.\ntype AppRunner interface",
+		"\t\t// orders must start before the workers that read its queue.\n\t\t\"orders\": orders.App{},",
+		`"billing": billing.App{},`,
+		`"example.com/shop/internal/billing"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("main file does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestAddAppToInternalMainSortsApps(t *testing.T) {
+	path := writeTestFile(t, "main.go", `package main
+
+func main() {
+	apps := map[string]AppRunner{}
+	for _, app := range apps {
+		app.Run()
+	}
+}
+`)
+	for _, app := range []string{"c", "a", "b"} {
+		if err := AddAppToInternalMain(path, "example.com/shop", app); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := readTestFile(t, path)
+	if want := `apps := map[string]AppRunner{"a": a.App{}, "b": b.App{}, "c": c.App{}}`; !strings.Contains(got, want) {
+		t.Errorf("main file does not contain %s:\n%s", want, got)
+	}
+}
+
+func TestAddModuleToAppMainSortsModules(t *testing.T) {
+	path := writeTestFile(t, "api_main.go", strings.Replace(testAppMain, "core.New(users.UsersModule{})", "core.New()", 1))
+	for _, name := range []string{"c", "a", "b"} {
+		if err := AddModuleToAppMain(path, "example.com/shop/internal/api/"+name, name, strings.ToUpper(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := readTestFile(t, path)
+	if want := "core.New(a.AModule{}, b.BModule{}, c.CModule{})"; !strings.Contains(got, want) {
+		t.Errorf("main file does not contain %s:\n%s", want, got)
+	}
+}
+
+func TestAddMiddlewareToAppMainPrecedesRoutes(t *testing.T) {
+	path := writeTestFile(t, "api_main.go", `package api
+
+import (
+	"net/http"
+
+	"example.com/shop/internal/api/core"
+	"example.com/shop/internal/api/metrics"
+)
+
+func run() {
+	app := core.New()
+
+	metrics.RegisterRoutes(app.Router())
+
+	srv := &http.Server{Handler: app.Router()}
+	srv.ListenAndServe()
+}
+`)
+	steps := []struct {
+		middleware bool
+		pkg, stmt  string
+	}{
+		{true, "errors", "app.Router().Use(errors.Middleware())"},
+		{false, "auth", `auth.RegisterRoutes(app.Router().Group("/auth"))`},
+		{true, "accesslog", "app.Router().Use(accesslog.Middleware())"},
+	}
+	for _, s := range steps {
+		add := AddStatementToAppMain
+		if s.middleware {
+			add = AddMiddlewareToAppMain
+		}
+		if err := add(path, "", "example.com/shop/internal/api/"+s.pkg, s.stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := readTestFile(t, path)
+	order := []string{
+		"app := core.New()",
+		"app.Router().Use(errors.Middleware())",
+		"app.Router().Use(accesslog.Middleware())",
+		"metrics.RegisterRoutes(app.Router())",
+		`auth.RegisterRoutes(app.Router().Group("/auth"))`,
+		"srv := &http.Server",
+	}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(got, want)
+		if i < 0 || i < last {
+			t.Fatalf("%s is out of order:\n%s", want, got)
+		}
+		last = i
+	}
+}
+
+func TestAddStatementToAppMainKeepsServerComment(t *testing.T) {
+	path := writeTestFile(t, "api_main.go", `package api
+
+import (
+	"net/http"
+
+	"example.com/shop/internal/api/core"
+)
+
+func run() {
+	app := core.New()
+
+	// The server stops on SIGTERM.
+	srv := &http.Server{Handler: app.Router()}
+	srv.ListenAndServe()
+}
+`)
+	if err := AddStatementToAppMain(path, "", "example.com/shop/internal/api/health", "health.RegisterRoutes(app.Router())"); err != nil {
+		t.Fatal(err)
+	}
+	got := readTestFile(t, path)
+	if want := "\t// The server stops on SIGTERM.\n\tsrv := &http.Server"; !strings.Contains(got, want) {
+		t.Errorf("main file does not contain %q:\n%s", want, got)
+	}
+}
+
+func TestAddStatementToAppMainRegistersRoutesInNewApp(t *testing.T) {
+	path := writeTestFile(t, "api_main.go", `package api
+
+import (
+	"net/http"
+
+	"example.com/shop/internal/api/core"
+)
+
+func run() {
+	app := newApp()
+
+	srv := &http.Server{Handler: app.Router()}
+	srv.ListenAndServe()
+}
+
+func newApp() *core.App {
+	app := core.New()
+
+	return app
+}
+`)
+	steps := []struct {
+		middleware bool
+		pkg, stmt  string
+	}{
+		{false, "health", "health.RegisterRoutes(app.Router())"},
+		{false, "pubsub", "defer pubsub.Start()()"},
+		{true, "errors", "app.Router().Use(errors.Middleware())"},
+	}
+	for _, s := range steps {
+		add := AddStatementToAppMain
+		if s.middleware {
+			add = AddMiddlewareToAppMain
+		}
+		if err := add(path, "", "example.com/shop/internal/api/"+s.pkg, s.stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := readTestFile(t, path)
+	order := []string{
+		"app := newApp()",
+		"defer pubsub.Start()()",
+		"srv := &http.Server",
+		"app := core.New()",
+		"app.Router().Use(errors.Middleware())",
+		"health.RegisterRoutes(app.Router())",
+		"return app",
+	}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(got, want)
+		if i < 0 || i < last {
+			t.Fatalf("%s is out of order:\n%s", want, got)
+		}
+		last = i
+	}
+}
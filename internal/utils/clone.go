@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CloneApp copies internal/<src> to internal/<dst>, renaming src to dst in
+// directory and file names, in import paths under the app, and in the Go
+// identifiers, strings, and comments of the copied files. The name is matched
+// as a whole word in its lower-case, Go (Orders), and environment (ORDERS)
+// forms, so "ordersService" and "ORDERS_PORT" are renamed but "reorders" is not.
+// Files other than Go sources are copied unchanged. It returns the files it wrote.
+func CloneApp(projectRoot, projectName, src, dst string) ([]string, error) {
+	srcDir := filepath.Join(projectRoot, "internal", src)
+	dstDir := filepath.Join(projectRoot, "internal", dst)
+	if _, err := os.Stat(srcDir); err != nil {
+		return nil, fmt.Errorf("app %q not found: %w", src, err)
+	}
+	if _, err := os.Stat(dstDir); err == nil {
+		return nil, fmt.Errorf("%s already exists", dstDir)
+	}
+
+	r := appRenamer{
+		from:      [3]string{src, GoName(src), EnvPrefix(src)},
+		to:        [3]string{dst, GoName(dst), EnvPrefix(dst)},
+		oldPrefix: projectName + "/internal/" + src,
+		newPrefix: projectName + "/internal/" + dst,
+	}
+
+	var written []string
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dstDir, r.renamePath(rel))
+		if d.IsDir() {
+			return os.MkdirAll(target, DirMode)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".go" {
+			if data, err = r.renameSource(path, data); err != nil {
+				return err
+			}
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := WriteFile(target, data, info.Mode().Perm()); err != nil {
+			return err
+		}
+		written = append(written, target)
+		return nil
+	})
+	return written, err
+}
+
+// appRenamer rewrites one app's name into another's.
+type appRenamer struct {
+	from, to             [3]string
+	oldPrefix, newPrefix string
+}
+
+// replace renames whole-word occurrences of the app name in identifiers and
+// paths. For an initialism such as "api" the Go and environment forms are both
+// API, so identifiers take the Go form of the new name.
+func (r appRenamer) replace(s string) string {
+	for i, old := range r.from {
+		s = replaceWord(s, old, r.to[i])
+	}
+	return s
+}
+
+// replaceText is replace for strings and comments, where an upper-case name is
+// more likely an environment variable prefix than part of an identifier.
+func (r appRenamer) replaceText(s string) string {
+	for _, i := range []int{2, 0, 1} {
+		s = replaceWord(s, r.from[i], r.to[i])
+	}
+	return s
+}
+
+// renamePath renames the app name in each element of a slash- or
+// filepath-separated path, including words of file names like orders_main.go.
+func (r appRenamer) renamePath(p string) string {
+	parts := strings.Split(filepath.ToSlash(p), "/")
+	for i, part := range parts {
+		parts[i] = r.replace(part)
+	}
+	return filepath.FromSlash(strings.Join(parts, "/"))
+}
+
+// renameImport rewrites import paths inside the source app; others are kept.
+func (r appRenamer) renameImport(importPath string) string {
+	if importPath == r.oldPrefix {
+		return r.newPrefix
+	}
+	if rest, ok := strings.CutPrefix(importPath, r.oldPrefix+"/"); ok {
+		return r.newPrefix + "/" + r.renamePath(rest)
+	}
+	return importPath
+}
+
+// renameSource renames the app in a Go file. Identifiers qualified by a package
+// from outside the app, e.g. log.Printf, are left alone.
+func (r appRenamer) renameSource(path string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	external := map[string]bool{}
+	importLits := map[*ast.BasicLit]bool{}
+	for _, imp := range node.Imports {
+		importLits[imp.Path] = true
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		if importPath == r.oldPrefix || strings.HasPrefix(importPath, r.oldPrefix+"/") {
+			continue
+		}
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		external[name] = true
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	add := func(n ast.Node, old, text string) {
+		if text != old {
+			edits = append(edits, edit{fset.Position(n.Pos()).Offset, fset.Position(n.End()).Offset, text})
+		}
+	}
+
+	skip := map[*ast.Ident]bool{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			if n.Name != nil && external[n.Name.Name] {
+				skip[n.Name] = true
+			}
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && x.Obj == nil && external[x.Name] {
+				skip[x] = true
+				skip[n.Sel] = true
+			}
+		case *ast.Ident:
+			if !skip[n] {
+				add(n, n.Name, r.replace(n.Name))
+			}
+		case *ast.BasicLit:
+			if n.Kind != token.STRING {
+				break
+			}
+			if importLits[n] {
+				importPath, _ := strconv.Unquote(n.Value)
+				add(n, n.Value, strconv.Quote(r.renameImport(importPath)))
+				break
+			}
+			add(n, n.Value, r.replaceText(n.Value))
+		}
+		return true
+	})
+	for _, cg := range node.Comments {
+		for _, c := range cg.List {
+			add(c, c.Text, r.replaceText(c.Text))
+		}
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var buf bytes.Buffer
+	pos := 0
+	for _, e := range edits {
+		buf.Write(src[pos:e.start])
+		buf.WriteString(e.text)
+		pos = e.end
+	}
+	buf.Write(src[pos:])
+	return format.Source(buf.Bytes())
+}
+
+// replaceWord replaces occurrences of old in s that stand as a word of their
+// own: not continued by a lower-case letter or digit, and, for a lower-case
+// old, not preceded by a letter or digit. Upper-case forms such as ORDERS must
+// also not touch other capitals, so ORDERSX is left alone.
+func replaceWord(s, old, new string) string {
+	if old == "" || old == new {
+		return s
+	}
+	oldRunes := []rune(old)
+	firstUpper := unicode.IsUpper(oldRunes[0])
+	allUpper := strings.ToUpper(old) == old && strings.ToLower(old) != old
+
+	var b strings.Builder
+	for {
+		i := strings.Index(s, old)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		before, after := lastRune(s[:i]), firstRune(s[i+len(old):])
+		ok := !unicode.IsLower(after) && !unicode.IsDigit(after)
+		if allUpper && (unicode.IsUpper(before) || unicode.IsUpper(after)) {
+			ok = false
+		}
+		if !firstUpper && (unicode.IsLetter(before) || unicode.IsDigit(before)) {
+			ok = false
+		}
+		b.WriteString(s[:i])
+		if ok {
+			b.WriteString(new)
+		} else {
+			b.WriteString(old)
+		}
+		s = s[i+len(old):]
+	}
+}
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
+
+func lastRune(s string) rune {
+	r := []rune(s)
+	if len(r) == 0 {
+		return 0
+	}
+	return r[len(r)-1]
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the project-level grob configuration file.
+const ConfigFileName = ".grobrc"
+
+// Config holds project-level settings read from .grobrc, on top of the
+// machine-wide defaults in the global config (see GlobalConfigPath).
+type Config struct {
+	// ImportPrefix is the import path prefix treated as the project's own code
+	// when grouping imports. It defaults to the module path from go.mod.
+	ImportPrefix string `yaml:"import_prefix,omitempty"`
+	// ResponseFormat selects how generated handlers write JSON: "raw" (gin.H) or "envelope".
+	ResponseFormat string `yaml:"response_format,omitempty"`
+	// Layout is "shared" (all apps run from internal/main.go) or "binaries"
+	// (each app gets its own cmd/<app>/main.go).
+	Layout string `yaml:"layout,omitempty"`
+	// Workspace makes each app its own module, used from the project's
+	// go.work, instead of a package of the project's module.
+	Workspace bool `yaml:"workspace,omitempty"`
+	// DirStyle controls where module directories live: "flat" (internal/<app>/<module>)
+	// or "modules"/"nested" (internal/<app>/modules/<module>).
+	DirStyle string `yaml:"dir_style,omitempty"`
+	// StructTags is the casing of generated JSON tags: "snake" (the default), "camel", or "pascal".
+	StructTags string `yaml:"struct_tags,omitempty"`
+	// TestFramework is the assertion style of generated tests: "stdlib"
+	// (t.Errorf, the default) or "testify" (assert and require).
+	TestFramework string `yaml:"test_framework,omitempty"`
+	// PrivateRepos is the GOPRIVATE value for private module dependencies,
+	// e.g. "github.com/acme/*". Generated CI and Docker files pass it on.
+	PrivateRepos string `yaml:"private_repos,omitempty"`
+	// GoVersion is the go directive of new projects' go.mod, e.g. "1.22".
+	GoVersion string `yaml:"go_version,omitempty"`
+	// FrameworkVersion is the grob-framework version new projects require.
+	FrameworkVersion string `yaml:"framework_version,omitempty"`
+	// Author and License are credited in new projects' README.
+	Author  string `yaml:"author,omitempty"`
+	License string `yaml:"license,omitempty"`
+}
+
+// Binaries reports whether the project builds each app as its own binary.
+func (c *Config) Binaries() bool {
+	return c.Layout == "binaries"
+}
+
+// LoadConfig reads .grobrc from the project root over the global config, so a
+// setting in .grobrc overrides the machine-wide default. Missing files yield
+// an empty config.
+func LoadConfig(projectRoot string) (*Config, error) {
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, readProjectConfig(projectRoot, cfg)
+}
+
+// LoadProjectConfig reads only the project's .grobrc, without global defaults.
+func LoadProjectConfig(projectRoot string) (*Config, error) {
+	var cfg Config
+	return &cfg, readProjectConfig(projectRoot, &cfg)
+}
+
+// readProjectConfig reads .grobrc over cfg, if the project has one.
+func readProjectConfig(projectRoot string, cfg *Config) error {
+	b, err := os.ReadFile(filepath.Join(projectRoot, ConfigFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("invalid %s: %w", ConfigFileName, err)
+	}
+	if err := ValidateDirStyle(cfg.DirStyle); err != nil {
+		return fmt.Errorf("invalid %s: %w", ConfigFileName, err)
+	}
+	if err := ValidateGoPrivate(cfg.PrivateRepos); err != nil {
+		return fmt.Errorf("invalid %s: %w", ConfigFileName, err)
+	}
+	switch cfg.TestFramework {
+	case "", TestStdlib, TestTestify:
+	default:
+		return fmt.Errorf("invalid %s: unknown test framework %q: use stdlib or testify", ConfigFileName, cfg.TestFramework)
+	}
+	return nil
+}
+
+// Test frameworks for generated tests.
+const (
+	TestStdlib  = "stdlib"
+	TestTestify = "testify"
+)
+
+// TemplateData returns the template data shared by every generator in a project.
+func TemplateData(projectRoot string) map[string]string {
+	cfg, err := LoadConfig(projectRoot)
+	if err != nil {
+		log.Fatalf("Could not read %s: %v", ConfigFileName, err)
+	}
+
+	projectName := GetProjectName(projectRoot)
+	importPrefix := cfg.ImportPrefix
+	if importPrefix == "" {
+		importPrefix = projectName
+	}
+	responseFormat := cfg.ResponseFormat
+	if responseFormat == "" {
+		responseFormat = "raw"
+	}
+	dirStyle := cfg.DirStyle
+	if dirStyle == "" {
+		dirStyle = DirStyleFlat
+	}
+	structTags := cfg.StructTags
+	if structTags == "" {
+		structTags = TagSnake
+	}
+	return map[string]string{
+		"ProjectName":    projectName,
+		"ImportPrefix":   importPrefix,
+		"ResponseFormat": responseFormat,
+		"DirStyle":       dirStyle,
+		"StructTags":     structTags,
+		"GoPrivate":      cfg.PrivateRepos,
+		// TestFramework stays empty unless set, so create-module only adds
+		// tests to projects that chose a framework; empty means stdlib.
+		"TestFramework": cfg.TestFramework,
+	}
+}
+
+// ValidateGoPrivate checks a comma-separated list of GOPRIVATE module path
+// patterns: each must be non-empty, contain no spaces, and be a valid glob.
+func ValidateGoPrivate(patterns string) error {
+	if patterns == "" {
+		return nil
+	}
+	for _, p := range strings.Split(patterns, ",") {
+		if p == "" || strings.ContainsAny(p, " \t") {
+			return fmt.Errorf("invalid private repo pattern %q", p)
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid private repo pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
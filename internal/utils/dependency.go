@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// Dependency is an extra constructor parameter injected into a generated service.
+type Dependency struct {
+	// Type is the Go type of the dependency, e.g. "*redis.Client".
+	Type string
+	// ImportPath is the package providing the type, if any.
+	ImportPath string
+	// Name is the field and parameter name, e.g. "redisClient".
+	Name string
+}
+
+// Constructor returns the name of the stub constructor that provides the dependency.
+func (d Dependency) Constructor() string {
+	return "new" + strings.Title(d.Name)
+}
+
+// ParseDependency parses a dependency flag of the form "TYPE" or "TYPE=IMPORT_PATH",
+// e.g. "*redis.Client=github.com/redis/go-redis/v9".
+func ParseDependency(s string) (Dependency, error) {
+	typ, importPath, _ := strings.Cut(s, "=")
+	typ = strings.TrimSpace(typ)
+	if _, err := parser.ParseExpr(typ); err != nil || typ == "" {
+		return Dependency{}, fmt.Errorf("invalid dependency type %q", typ)
+	}
+
+	base := strings.TrimLeft(typ, "*[]")
+	var name string
+	if pkg, ident, ok := strings.Cut(base, "."); ok {
+		name = pkg + strings.Title(ident)
+	} else {
+		name = strings.ToLower(base[:1]) + base[1:]
+	}
+	if !token.IsIdentifier(name) {
+		return Dependency{}, fmt.Errorf("cannot derive a field name from dependency type %q", typ)
+	}
+	return Dependency{Type: typ, ImportPath: strings.TrimSpace(importPath), Name: name}, nil
+}
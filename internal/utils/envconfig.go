@@ -0,0 +1,283 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVar is an environment variable a project reads, found by ScanEnvVars or
+// declared in an env spec.
+type EnvVar struct {
+	Name string `yaml:"name"`
+	// Type is one of the keys of EnvTypes; it defaults to "string".
+	Type        string `yaml:"type"`
+	Required    bool   `yaml:"required"`
+	Default     string `yaml:"default"`
+	Description string `yaml:"description"`
+	// Files are the project files, relative to the root, that read the variable.
+	Files []string `yaml:"-"`
+}
+
+// EnvTypes maps each env var type to the Go type of its config field.
+var EnvTypes = map[string]string{
+	"string":   "string",
+	"int":      "int",
+	"bool":     "bool",
+	"float":    "float64",
+	"duration": "time.Duration",
+	"list":     "[]string",
+}
+
+var envVarName = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// ScanEnvVars finds the environment variables read by the project's Go files:
+// os.Getenv and os.LookupEnv calls, and calls to helpers whose name contains
+// "env", such as getenv("QUEUE_URL", "...") or durationFromEnv("X", time.Minute),
+// that take the variable name as a string literal. A helper's second argument
+// is taken as the default, and a type word in a helper's name (duration, int,
+// bool, float) sets the variable's type. Hidden directories, vendor, testdata,
+// test files, paths excluded by ignore, and the skipped paths are not scanned.
+func ScanEnvVars(projectRoot string, ignore *Ignore, skip ...string) ([]EnvVar, error) {
+	vars := map[string]*EnvVar{}
+	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			name := d.Name()
+			if rel != "." && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || ignore.Ignored(rel, true)) {
+				return filepath.SkipDir
+			}
+			for _, s := range skip {
+				if rel == s {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") || ignore.Ignored(rel, false) {
+			return nil
+		}
+
+		node, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(node, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			v, ok := envVarFromCall(call)
+			if !ok {
+				return true
+			}
+			if existing, ok := vars[v.Name]; ok {
+				if !contains(existing.Files, rel) {
+					existing.Files = append(existing.Files, rel)
+				}
+				if existing.Default == "" {
+					existing.Default = v.Default
+				}
+				return true
+			}
+			v.Files = []string{rel}
+			vars[v.Name] = &v
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]EnvVar, 0, len(vars))
+	for _, v := range vars {
+		list = append(list, *v)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// envVarFromCall recognizes a call that reads an environment variable.
+func envVarFromCall(call *ast.CallExpr) (EnvVar, bool) {
+	if len(call.Args) == 0 {
+		return EnvVar{}, false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return EnvVar{}, false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil || !envVarName.MatchString(name) {
+		return EnvVar{}, false
+	}
+
+	var funcName string
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		funcName = fn.Name
+	case *ast.SelectorExpr:
+		if x, ok := fn.X.(*ast.Ident); ok && x.Name == "os" {
+			if fn.Sel.Name == "Getenv" || fn.Sel.Name == "LookupEnv" {
+				return EnvVar{Name: name, Type: "string"}, true
+			}
+			return EnvVar{}, false
+		}
+		funcName = fn.Sel.Name
+	default:
+		return EnvVar{}, false
+	}
+	if !strings.Contains(strings.ToLower(funcName), "env") {
+		return EnvVar{}, false
+	}
+
+	v := EnvVar{Name: name, Type: "string"}
+	for _, word := range SplitWords(funcName) {
+		switch w := strings.ToLower(word); w {
+		case "duration", "int", "bool", "float":
+			v.Type = w
+		}
+	}
+	if len(call.Args) > 1 {
+		v.Default = defaultValue(call.Args[1], v.Type)
+	}
+	return v, true
+}
+
+// defaultValue renders a helper's default argument as an env var value, or
+// returns "" when it is not a constant this package understands.
+func defaultValue(expr ast.Expr, typ string) string {
+	if typ == "duration" {
+		if d, ok := durationValue(expr); ok {
+			return d.String()
+		}
+		return ""
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			s, _ := strconv.Unquote(e.Value)
+			return s
+		}
+		return e.Value
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return e.Name
+		}
+	}
+	return ""
+}
+
+// durationValue evaluates time.Minute, 5 * time.Second, and similar constants.
+func durationValue(expr ast.Expr) (time.Duration, bool) {
+	units := map[string]time.Duration{
+		"Nanosecond": time.Nanosecond, "Microsecond": time.Microsecond, "Millisecond": time.Millisecond,
+		"Second": time.Second, "Minute": time.Minute, "Hour": time.Hour,
+	}
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "time" {
+			d, ok := units[e.Sel.Name]
+			return d, ok
+		}
+	case *ast.BasicLit:
+		if n, err := strconv.ParseInt(e.Value, 0, 64); err == nil && e.Kind == token.INT {
+			return time.Duration(n), true
+		}
+	case *ast.ParenExpr:
+		return durationValue(e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.MUL {
+			return 0, false
+		}
+		x, ok := durationValue(e.X)
+		if !ok {
+			return 0, false
+		}
+		y, ok := durationValue(e.Y)
+		return x * y, ok
+	}
+	return 0, false
+}
+
+// LoadEnvSpec reads a YAML file declaring environment variables under "vars".
+func LoadEnvSpec(path string) ([]EnvVar, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec struct {
+		Vars []EnvVar `yaml:"vars"`
+	}
+	if err := yaml.Unmarshal(b, &spec); err != nil {
+		return nil, fmt.Errorf("invalid env spec %s: %w", path, err)
+	}
+	for i := range spec.Vars {
+		v := &spec.Vars[i]
+		if v.Type == "" {
+			v.Type = "string"
+		}
+		if err := ValidateEnvVar(*v); err != nil {
+			return nil, fmt.Errorf("invalid env spec %s: %w", path, err)
+		}
+	}
+	return spec.Vars, nil
+}
+
+// ValidateEnvVar checks an env var's name and type, and that its default parses as that type.
+func ValidateEnvVar(v EnvVar) error {
+	if !envVarName.MatchString(v.Name) {
+		return fmt.Errorf("invalid variable name %q: use upper-case letters, digits, and underscores", v.Name)
+	}
+	if _, ok := EnvTypes[v.Type]; !ok {
+		return fmt.Errorf("%s: unknown type %q: use string, int, bool, float, duration, or list", v.Name, v.Type)
+	}
+	if strings.ContainsAny(v.Default, "`\n") {
+		return fmt.Errorf("%s: the default may not contain backquotes or newlines", v.Name)
+	}
+	if v.Default == "" {
+		return nil
+	}
+	var err error
+	switch v.Type {
+	case "int":
+		_, err = strconv.Atoi(v.Default)
+	case "bool":
+		_, err = strconv.ParseBool(v.Default)
+	case "float":
+		_, err = strconv.ParseFloat(v.Default, 64)
+	case "duration":
+		_, err = time.ParseDuration(v.Default)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: invalid %s default %q", v.Name, v.Type, v.Default)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -1,33 +1,162 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"text/template"
+
+	"github.com/yuliussmayoru/grob-cli/internal/templates"
+)
+
+// GeneratedBanner starts the first line of the Go files grob fully owns and
+// regenerates, such as pkg/config/config.go, which "grob clean --generated"
+// may remove. Scaffolded files meant to be edited by hand never carry it.
+const GeneratedBanner = "// Code generated by grob"
+
+// Permissions used for everything grob writes. They are largely ignored on Windows.
+const (
+	// FileMode is the default mode for generated source files.
+	FileMode os.FileMode = 0644
+	// ExecMode is the mode for generated scripts that must be executable.
+	ExecMode os.FileMode = 0755
+	// DirMode is the mode for generated directories.
+	DirMode os.FileMode = 0755
 )
 
-// CreateFileFromTmpl executes a template and writes it to a file.
+// FileModeFor returns the mode a generated file should have: ExecMode for shell
+// scripts, FileMode for everything else.
+func FileModeFor(path string) os.FileMode {
+	if filepath.Ext(path) == ".sh" {
+		return ExecMode
+	}
+	return FileMode
+}
+
+// CreateFileFromTmpl executes a template and writes it to a file with the mode
+// returned by FileModeFor. It exits on failure; see WriteFileFromTmpl.
 func CreateFileFromTmpl(path, tmplStr string, data map[string]string) {
-	tmpl, err := template.New("").Funcs(template.FuncMap{"Title": strings.Title}).Parse(tmplStr)
-	if err != nil {
-		log.Fatalf("Failed to parse template for %s: %v", path, err)
+	CreateFileFromTmplMode(path, tmplStr, data, FileModeFor(path))
+}
+
+// CreateFileFromTmplMode executes a template and writes it to a file with the given mode.
+// Go files are gofmt'ed with their imports grouped into std, third-party, and
+// local sections, using data["ImportPrefix"] (or data["ProjectName"]) as the local prefix.
+func CreateFileFromTmplMode(path, tmplStr string, data map[string]string, perm os.FileMode) {
+	if err := writeTemplate(path, "", tmplStr, data, perm, false); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// CreateFileFromCustomTmpl is CreateFileFromTmpl for user-provided templates.
+// Referencing a key that is not in data is an error rather than "<no value>",
+// since it usually means a --var was not passed. Errors are reported as a
+// *TemplateError at their line in tmplPath, the file tmplStr was read from.
+func CreateFileFromCustomTmpl(path, tmplPath, tmplStr string, data map[string]string) {
+	if err := WriteFileFromCustomTmpl(path, tmplPath, tmplStr, data); err != nil {
+		log.Fatal(err)
 	}
+}
+
+// WriteFileFromTmpl is CreateFileFromTmpl for callers that handle errors
+// themselves, such as generation steps that report the files they wrote.
+func WriteFileFromTmpl(path, tmplStr string, data map[string]string) error {
+	return writeTemplate(path, "", tmplStr, data, FileModeFor(path), false)
+}
+
+// WriteFileFromCustomTmpl is CreateFileFromCustomTmpl returning errors instead of exiting.
+func WriteFileFromCustomTmpl(path, tmplPath, tmplStr string, data map[string]string) error {
+	return writeTemplate(path, tmplPath, tmplStr, data, FileModeFor(path), true)
+}
 
-	f, err := os.Create(path)
+// writeTemplate renders tmplStr to path. source is the file a user-provided
+// template was read from, and is empty for built-in templates.
+func writeTemplate(path, source, tmplStr string, data map[string]string, perm os.FileMode, strict bool) error {
+	tmpl := template.New(source).Funcs(templates.Funcs)
+	if strict {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+	tmpl, err := tmpl.Parse(tmplStr)
 	if err != nil {
-		log.Fatalf("Failed to create file %s: %v", path, err)
+		if source != "" {
+			err = newTemplateError(source, tmplStr, err)
+		}
+		return fmt.Errorf("failed to parse template for %s: %w", path, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		if source != "" {
+			err = newTemplateError(source, tmplStr, err)
+		}
+		return fmt.Errorf("failed to execute template for %s: %w", path, err)
+	}
+
+	out := buf.Bytes()
+	if filepath.Ext(path) == ".go" {
+		prefix := data["ImportPrefix"]
+		if prefix == "" {
+			prefix = data["ProjectName"]
+		}
+		// Leave output that is not valid Go as-is so the user can see what went wrong.
+		if formatted, err := GroupImports(out, prefix); err == nil {
+			out = formatted
+		}
+	}
+
+	if err := WriteFile(path, out, perm); err != nil {
+		return fmt.Errorf("failed to create file %s: %w", path, err)
+	}
+	return nil
+}
+
+// created lists the files WriteFile created, in order.
+var created []string
+
+// CreatedFiles returns the files WriteFile, and so every template helper, has
+// created in this run, in the order they were created. Files that existed
+// before are not included.
+func CreatedFiles() []string {
+	return created
+}
+
+// WriteFile writes data to path and makes sure the file ends up with perm,
+// even when it already existed with a different mode. Windows only knows a
+// read-only bit, so the mode is left to os.WriteFile there.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	_, statErr := os.Stat(path)
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+	if os.IsNotExist(statErr) {
+		created = append(created, path)
 	}
-	defer f.Close()
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	return os.Chmod(path, perm)
+}
 
-	if err := tmpl.Execute(f, data); err != nil {
-		log.Fatalf("Failed to execute template for %s: %v", path, err)
+// RenderString executes a template and returns the result as a string.
+func RenderString(tmplStr string, data map[string]string) (string, error) {
+	tmpl, err := template.New("").Funcs(templates.Funcs).Parse(tmplStr)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
 	}
+	return sb.String(), nil
 }
 
 // FindProjectRoot finds the root of the Grob project by looking for a go.mod file.
+// In a workspace project, where apps have go.mod files of their own, it is the
+// directory above that has both go.mod and go.work.
 func FindProjectRoot() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -35,7 +164,7 @@ func FindProjectRoot() (string, error) {
 	}
 	for {
 		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
-			return dir, nil
+			return workspaceRoot(dir), nil
 		}
 		if dir == filepath.Dir(dir) {
 			return "", fmt.Errorf("go.mod not found in any parent directory")
@@ -44,6 +173,21 @@ func FindProjectRoot() (string, error) {
 	}
 }
 
+// workspaceRoot returns the nearest directory at or above moduleDir holding
+// both go.mod and go.work, or moduleDir if there is none.
+func workspaceRoot(moduleDir string) string {
+	for dir := moduleDir; ; dir = filepath.Dir(dir) {
+		_, errMod := os.Stat(filepath.Join(dir, "go.mod"))
+		_, errWork := os.Stat(filepath.Join(dir, WorkFileName))
+		if errMod == nil && errWork == nil {
+			return dir
+		}
+		if dir == filepath.Dir(dir) {
+			return moduleDir
+		}
+	}
+}
+
 // GetProjectName reads the module name from the go.mod file.
 func GetProjectName(projectRoot string) string {
 	goModBytes, err := os.ReadFile(filepath.Join(projectRoot, "go.mod"))
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigKey is a setting that can be given a machine-wide default with
+// "grob config set". Settings that describe an existing project's shape, such
+// as layout and dir_style, are deliberately not among them: a default would
+// change how grob reads projects created before it was set.
+type ConfigKey struct {
+	// Name is the key on the command line, e.g. "go-version".
+	Name        string
+	Description string
+	// Default is the value used when neither config sets the key.
+	Default string
+	field   func(*Config) *string
+	valid   func(string) error
+}
+
+// Get returns the key's value in cfg, which is empty when it is not set.
+func (k ConfigKey) Get(cfg *Config) string {
+	return *k.field(cfg)
+}
+
+// Set validates value and sets the key in cfg. An empty value unsets it.
+func (k ConfigKey) Set(cfg *Config, value string) error {
+	if value != "" {
+		if strings.ContainsAny(value, "\n\r") {
+			return fmt.Errorf("%s: the value may not contain newlines", k.Name)
+		}
+		if k.valid != nil {
+			if err := k.valid(value); err != nil {
+				return fmt.Errorf("%s: %w", k.Name, err)
+			}
+		}
+	}
+	*k.field(cfg) = value
+	return nil
+}
+
+var goVersion = regexp.MustCompile(`^1\.[0-9]+(\.[0-9]+)?$`)
+
+// ConfigKeys lists the settings "grob config" accepts.
+var ConfigKeys = []ConfigKey{
+	{
+		Name: "go-version", Description: "go directive of new projects' go.mod", Default: "1.19",
+		field: func(c *Config) *string { return &c.GoVersion },
+		valid: func(v string) error {
+			if !goVersion.MatchString(v) {
+				return fmt.Errorf("invalid Go version %q, e.g. 1.22", v)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "framework-version", Description: "grob-framework version new projects require", Default: "v0.1.0",
+		field: func(c *Config) *string { return &c.FrameworkVersion },
+		valid: func(v string) error {
+			if !semver.IsValid(v) {
+				return fmt.Errorf("invalid module version %q, e.g. v0.2.0", v)
+			}
+			return nil
+		},
+	},
+	{
+		Name: "author", Description: "copyright holder credited in new projects' README",
+		field: func(c *Config) *string { return &c.Author },
+	},
+	{
+		Name: "license", Description: "license named in new projects' README, e.g. MIT",
+		field: func(c *Config) *string { return &c.License },
+	},
+	{
+		Name: "response-format", Description: "JSON style of generated handlers: raw or envelope", Default: "raw",
+		field: func(c *Config) *string { return &c.ResponseFormat },
+		valid: oneOf("raw", "envelope"),
+	},
+	{
+		Name: "struct-tags", Description: "casing of generated JSON tags: snake, camel, or pascal", Default: TagSnake,
+		field: func(c *Config) *string { return &c.StructTags },
+		valid: oneOf(TagSnake, TagCamel, TagPascal),
+	},
+	{
+		Name: "test-framework", Description: "assertions of generated tests: stdlib or testify", Default: TestStdlib,
+		field: func(c *Config) *string { return &c.TestFramework },
+		valid: oneOf(TestStdlib, TestTestify),
+	},
+	{
+		Name: "private-repos", Description: "GOPRIVATE patterns for private dependencies, e.g. github.com/acme/*",
+		field: func(c *Config) *string { return &c.PrivateRepos },
+		valid: ValidateGoPrivate,
+	},
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		for _, allowed := range values {
+			if v == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q: use %s", v, strings.Join(values, " or "))
+	}
+}
+
+// LookupConfigKey returns the named setting, or an error listing the known ones.
+func LookupConfigKey(name string) (ConfigKey, error) {
+	names := make([]string, len(ConfigKeys))
+	for i, k := range ConfigKeys {
+		if k.Name == name {
+			return k, nil
+		}
+		names[i] = k.Name
+	}
+	return ConfigKey{}, fmt.Errorf("unknown config key %q: use one of %s", name, strings.Join(names, ", "))
+}
+
+// GlobalConfigPath returns the path of the machine-wide config, ~/.grob/config.yaml.
+func GlobalConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".grob", "config.yaml"), nil
+}
+
+// LoadGlobalConfig reads the machine-wide config. A missing file, or a missing
+// home directory, yields an empty config.
+func LoadGlobalConfig() (*Config, error) {
+	var cfg Config
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return &cfg, nil
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// SaveGlobalConfig writes the machine-wide config, creating ~/.grob if needed.
+func SaveGlobalConfig(cfg *Config) error {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), DirMode); err != nil {
+		return err
+	}
+	return WriteFile(path, b, FileMode)
+}
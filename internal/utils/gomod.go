@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// AddRequire adds a require directive to the project's go.mod unless the module is already required.
+func AddRequire(projectRoot, modulePath, version string) error {
+	path := filepath.Join(projectRoot, "go.mod")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := modfile.Parse(path, b, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range f.Require {
+		if r.Mod.Path == modulePath {
+			return nil
+		}
+	}
+	if err := f.AddRequire(modulePath, version); err != nil {
+		return err
+	}
+
+	f.SortBlocks()
+	f.Cleanup()
+	out, err := f.Format()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, FileMode)
+}
+
+// GoVersion returns the go directive of the project's go.mod, e.g. "1.19".
+func GoVersion(projectRoot string) (string, error) {
+	path := filepath.Join(projectRoot, "go.mod")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := modfile.ParseLax(path, b, nil)
+	if err != nil {
+		return "", err
+	}
+	if f.Go == nil {
+		return "", fmt.Errorf("%s has no go directive", path)
+	}
+	return f.Go.Version, nil
+}
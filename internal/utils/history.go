@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFile is where a project's scaffolding history is kept, relative to
+// the project root: one JSON object per line, appended by each generating
+// command.
+const HistoryFile = ".grob/history.jsonl"
+
+// HistoryEntry records one generating command.
+type HistoryEntry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args,omitempty"`
+	// Flags holds the flags set on the command line. Values of flags that may
+	// hold secrets are replaced by RedactedValue.
+	Flags map[string]string `json:"flags,omitempty"`
+	// CreatedFiles are slash-separated paths relative to the project root.
+	CreatedFiles []string `json:"created_files,omitempty"`
+}
+
+// RedactedValue replaces flag values that are not recorded in the history.
+const RedactedValue = "[redacted]"
+
+// AppendHistory adds an entry to the project's HistoryFile, creating it if needed.
+func AppendHistory(projectRoot string, entry HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectRoot, filepath.FromSlash(HistoryFile))
+	if err := os.MkdirAll(filepath.Dir(path), DirMode); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, FileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the file in the project root listing directories that
+// grob's app and module scanners skip, such as shared or generated code.
+const IgnoreFileName = ".grobignore"
+
+// Ignore holds the rules of a .grobignore file. The syntax follows .gitignore:
+// blank lines and lines starting with # are skipped, ! re-includes a path, a
+// trailing / matches directories only, and a pattern containing a / is
+// matched from the project root while one without is matched against every
+// path element. * and ? do not cross a /, and ** matches any number of
+// directories.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// LoadIgnore reads .grobignore from the project root. A missing file ignores nothing.
+func LoadIgnore(projectRoot string) (*Ignore, error) {
+	b, err := os.ReadFile(filepath.Join(projectRoot, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return &Ignore{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ig Ignore
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var r ignoreRule
+		if r.negate = strings.HasPrefix(text, "!"); r.negate {
+			text = text[1:]
+		}
+		if r.dirOnly = strings.HasSuffix(text, "/"); r.dirOnly {
+			text = strings.TrimRight(text, "/")
+		}
+		r.anchored = strings.Contains(text, "/")
+		r.pattern = strings.TrimPrefix(text, "/")
+		if _, err := path.Match(strings.ReplaceAll(r.pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid %s line %d: %w", IgnoreFileName, line, err)
+		}
+		ig.rules = append(ig.rules, r)
+	}
+	return &ig, scanner.Err()
+}
+
+// Ignored reports whether a path relative to the project root is ignored,
+// either itself or because one of its parent directories is. The last rule
+// matching a path decides, as in .gitignore.
+func (ig *Ignore) Ignored(rel string, isDir bool) bool {
+	if ig == nil || len(ig.rules) == 0 {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(rel)), "/")
+	for i := 1; i <= len(parts); i++ {
+		if ig.match(parts[:i], i < len(parts) || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ig *Ignore) match(parts []string, isDir bool) bool {
+	ignored := false
+	for _, r := range ig.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if r.anchored {
+			ok = matchParts(strings.Split(r.pattern, "/"), parts)
+		} else {
+			ok = matchParts([]string{r.pattern}, parts[len(parts)-1:])
+		}
+		if ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchParts matches path elements against pattern elements, where a "**"
+// element matches zero or more path elements.
+func matchParts(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchParts(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], parts[0])
+	return ok && matchParts(pattern[1:], parts[1:])
+}
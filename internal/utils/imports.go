@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GroupImports rewrites the grouped import block of a Go source file into
+// standard library, third-party, and local sections, goimports-style.
+// Imports starting with localPrefix are placed last.
+func GroupImports(src []byte, localPrefix string) ([]byte, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var decl *ast.GenDecl
+	for _, d := range node.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT && gd.Lparen.IsValid() {
+			decl = gd
+			break
+		}
+	}
+	if decl == nil {
+		return format.Source(src)
+	}
+
+	var std, external, local []string
+	for _, spec := range decl.Specs {
+		is := spec.(*ast.ImportSpec)
+		path, err := strconv.Unquote(is.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		line := is.Path.Value
+		if is.Name != nil {
+			line = is.Name.Name + " " + line
+		}
+		switch {
+		case localPrefix != "" && (path == localPrefix || strings.HasPrefix(path, localPrefix+"/")):
+			local = append(local, line)
+		case !strings.Contains(strings.SplitN(path, "/", 2)[0], "."):
+			std = append(std, line)
+		default:
+			external = append(external, line)
+		}
+	}
+
+	var groups []string
+	for _, group := range [][]string{std, external, local} {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return importPathOf(group[i]) < importPathOf(group[j]) })
+		groups = append(groups, strings.Join(group, "\n"))
+	}
+
+	// Drop an empty block and write a lone import without parentheses.
+	var buf bytes.Buffer
+	switch len(decl.Specs) {
+	case 0:
+		buf.Write(src[:fset.Position(decl.Pos()).Offset])
+		buf.Write(src[fset.Position(decl.End()).Offset:])
+	case 1:
+		buf.Write(src[:fset.Position(decl.Pos()).Offset])
+		buf.WriteString("import " + groups[0])
+		buf.Write(src[fset.Position(decl.End()).Offset:])
+	default:
+		buf.Write(src[:fset.Position(decl.Lparen).Offset+1])
+		buf.WriteString("\n" + strings.Join(groups, "\n\n") + "\n")
+		buf.Write(src[fset.Position(decl.Rparen).Offset:])
+	}
+	return format.Source(buf.Bytes())
+}
+
+// importPathOf strips an optional import name from an import line.
+func importPathOf(line string) string {
+	if i := strings.Index(line, `"`); i > 0 {
+		return line[i:]
+	}
+	return line
+}
@@ -0,0 +1,105 @@
+package utils
+
+import "testing"
+
+func TestGroupImports(t *testing.T) {
+	tests := []struct {
+		name, prefix, src, want string
+	}{
+		{
+			name:   "sections",
+			prefix: "example.com/shop",
+			src: `package api
+
+import (
+	"example.com/shop/internal/api/core"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	apperrors "example.com/shop/internal/api/errors"
+	"context"
+)
+`,
+			want: `package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"example.com/shop/internal/api/core"
+	apperrors "example.com/shop/internal/api/errors"
+)
+`,
+		},
+		{
+			name:   "no local prefix",
+			prefix: "",
+			src: `package api
+
+import (
+	"example.com/shop/internal/api/core"
+	"fmt"
+)
+`,
+			want: `package api
+
+import (
+	"fmt"
+
+	"example.com/shop/internal/api/core"
+)
+`,
+		},
+		{
+			name:   "lone import",
+			prefix: "example.com/shop",
+			src: `package api
+
+import (
+	"fmt"
+)
+`,
+			want: `package api
+
+import "fmt"
+`,
+		},
+		{
+			name:   "empty block",
+			prefix: "example.com/shop",
+			src: `package api
+
+import ()
+
+func f() {}
+`,
+			want: `package api
+
+func f() {}
+`,
+		},
+		{
+			name:   "ungrouped import",
+			prefix: "example.com/shop",
+			src: `package api
+
+import "fmt"
+`,
+			want: `package api
+
+import "fmt"
+`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := GroupImports([]byte(tt.src), tt.prefix)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: GroupImports =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
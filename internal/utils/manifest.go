@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFileName is the name of the manifest inside a module template directory.
+const ManifestFileName = "manifest.yaml"
+
+// ManifestEntry describes a single file rendered by a module template manifest.
+type ManifestEntry struct {
+	// Name is the output file name. It is itself a template, e.g. "{{.ModuleName}}.repository.go".
+	Name string `yaml:"name"`
+	// Template is the template file, relative to the manifest directory.
+	Template string `yaml:"template"`
+	// Provide is an optional constructor registered in the module's Register method.
+	Provide string `yaml:"provide"`
+}
+
+// Manifest lists the files a custom module template generates.
+type Manifest struct {
+	Files []ManifestEntry `yaml:"files"`
+}
+
+// LoadManifest reads the manifest from a module template directory.
+// It returns nil without an error when the directory has no manifest.
+func LoadManifest(dir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFileName, err)
+	}
+	if len(m.Files) == 0 {
+		return nil, fmt.Errorf("%s declares no files", ManifestFileName)
+	}
+	for i, f := range m.Files {
+		if f.Name == "" || f.Template == "" {
+			return nil, fmt.Errorf("%s: entry %d needs both a name and a template", ManifestFileName, i+1)
+		}
+		if _, err := JoinWithin(dir, f.Template); err != nil {
+			return nil, fmt.Errorf("%s: entry %d: template %w", ManifestFileName, i+1, err)
+		}
+	}
+	return &m, nil
+}
+
+// JoinWithin joins rel to dir, as for the name and template of a manifest
+// entry, and fails if rel is absolute or leads outside dir.
+func JoinWithin(dir, rel string) (string, error) {
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return "", fmt.Errorf("%q is an absolute path", rel)
+	}
+	path := filepath.Join(dir, rel)
+	r, err := filepath.Rel(dir, path)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%q is outside %s", rel, dir)
+	}
+	return path, nil
+}
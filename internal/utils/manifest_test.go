@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJoinWithin(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "module")
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{"users.go", filepath.Join(dir, "users.go")},
+		{"sub/users.go", filepath.Join(dir, "sub", "users.go")},
+		{"sub/../users.go", filepath.Join(dir, "users.go")},
+		{"../users.go", ""},
+		{"../../../escaped.go", ""},
+		{"sub/../../users.go", ""},
+		{"..", ""},
+		{filepath.Join(dir, "users.go"), ""},
+	}
+	for _, tt := range tests {
+		got, err := JoinWithin(dir, tt.rel)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("JoinWithin(%q) = %s; want an error", tt.rel, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("JoinWithin(%q) = %s, %v; want %s", tt.rel, got, err, tt.want)
+		}
+	}
+}
+
+func TestLoadManifestRejectsEscapingTemplate(t *testing.T) {
+	dir := t.TempDir()
+	manifest := "files:\n  - name: x.go\n    template: ../../go.mod\n"
+	if err := os.WriteFile(filepath.Join(dir, ManifestFileName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadManifest(dir); err == nil {
+		t.Error("LoadManifest accepted a template outside its directory")
+	}
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// Param is a parameter of a parsed method.
+type Param struct {
+	Name     string
+	Type     string
+	Variadic bool
+}
+
+// Method is an exported method parsed from a Go source file.
+type Method struct {
+	Name    string
+	Params  []Param
+	Results []string
+	// Imports maps the package identifiers used in the signature to their import specs,
+	// e.g. "context" -> `"context"` or "redis" -> `redis "github.com/redis/go-redis/v9"`.
+	Imports map[string]string
+}
+
+// Signature returns the method's parameter and result lists, e.g. "(ctx context.Context) (string, error)".
+func (m Method) Signature() string {
+	params := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		t := p.Type
+		if p.Variadic {
+			t = "..." + t
+		}
+		params[i] = p.Name + " " + t
+	}
+	sig := "(" + strings.Join(params, ", ") + ")"
+	switch len(m.Results) {
+	case 0:
+	case 1:
+		sig += " " + m.Results[0]
+	default:
+		sig += " (" + strings.Join(m.Results, ", ") + ")"
+	}
+	return sig
+}
+
+// CallArgs returns the arguments used to forward a call, e.g. "ctx, ids...".
+func (m Method) CallArgs() string {
+	args := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		args[i] = p.Name
+		if p.Variadic {
+			args[i] += "..."
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// ParseMethods returns the exported methods declared on typeName (or *typeName) in a file.
+// Unnamed parameters are given names so that the signatures can be reused for forwarding.
+func ParseMethods(path, typeName string) ([]Method, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := map[string]string{}
+	for _, imp := range node.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		spec := imp.Path.Value
+		if imp.Name != nil {
+			name = imp.Name.Name
+			spec = name + " " + spec
+		}
+		imports[name] = spec
+	}
+
+	var methods []Method
+	for _, decl := range node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || !fd.Name.IsExported() || receiverTypeName(fd.Recv) != typeName {
+			continue
+		}
+
+		m := Method{Name: fd.Name.Name, Imports: map[string]string{}}
+		collect := func(expr ast.Expr) string {
+			ast.Inspect(expr, func(n ast.Node) bool {
+				if se, ok := n.(*ast.SelectorExpr); ok {
+					if x, ok := se.X.(*ast.Ident); ok {
+						if spec, ok := imports[x.Name]; ok {
+							m.Imports[x.Name] = spec
+						}
+					}
+				}
+				return true
+			})
+			return exprSource(fset, src, node.Comments, expr)
+		}
+
+		for i, field := range fd.Type.Params.List {
+			typ := field.Type
+			variadic := false
+			if el, ok := typ.(*ast.Ellipsis); ok {
+				typ, variadic = el.Elt, true
+			}
+			typeSrc := collect(typ)
+			if len(field.Names) == 0 {
+				m.Params = append(m.Params, Param{Name: fmt.Sprintf("arg%d", i), Type: typeSrc, Variadic: variadic})
+				continue
+			}
+			for _, name := range field.Names {
+				paramName := name.Name
+				if paramName == "_" {
+					paramName = fmt.Sprintf("arg%d", len(m.Params))
+				}
+				m.Params = append(m.Params, Param{Name: paramName, Type: typeSrc, Variadic: variadic})
+			}
+		}
+
+		if fd.Type.Results != nil {
+			for _, field := range fd.Type.Results.List {
+				typeSrc := collect(field.Type)
+				n := len(field.Names)
+				if n == 0 {
+					n = 1
+				}
+				for i := 0; i < n; i++ {
+					m.Results = append(m.Results, typeSrc)
+				}
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// receiverTypeName returns the base type name of a method receiver.
+func receiverTypeName(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ModelField is an exported field of a parsed model struct.
+type ModelField struct {
+	Name string
+	Type string
+	// Column is the field's database column: its db tag if it has one,
+	// otherwise the snake_case form of Name.
+	Column string
+}
+
+// Model is a struct type parsed from a Go source file.
+type Model struct {
+	Name   string
+	Fields []ModelField
+	// Path is the file declaring the model.
+	Path string
+}
+
+// ParseModels returns the struct types declared in a file with their exported
+// fields. Embedded fields and fields tagged db:"-" are skipped.
+func ParseModels(path string) ([]Model, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []Model
+	for _, decl := range node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			model := Model{Name: ts.Name.Name, Path: path}
+			for _, f := range st.Fields.List {
+				column := ""
+				if f.Tag != nil {
+					column = reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("db")
+				}
+				if column == "-" {
+					continue
+				}
+				for _, name := range f.Names {
+					if !name.IsExported() {
+						continue
+					}
+					field := ModelField{Name: name.Name, Type: exprSource(fset, src, node.Comments, f.Type), Column: column}
+					if field.Column == "" || len(f.Names) > 1 {
+						field.Column = TagName(name.Name, TagSnake)
+					}
+					model.Fields = append(model.Fields, field)
+				}
+			}
+			models = append(models, model)
+		}
+	}
+	return models, nil
+}
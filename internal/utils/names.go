@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// stdlibPackages holds the names of standard library packages that a generated
+// module package would shadow when imported by its plain name.
+var stdlibPackages = map[string]bool{
+	"bufio": true, "bytes": true, "cmp": true, "context": true, "crypto": true,
+	"embed": true, "encoding": true, "errors": true, "expvar": true, "flag": true,
+	"fmt": true, "hash": true, "html": true, "image": true, "io": true,
+	"iter": true, "json": true, "log": true, "maps": true, "math": true,
+	"mime": true, "net": true, "os": true, "path": true, "plugin": true,
+	"reflect": true, "regexp": true, "runtime": true, "slices": true, "sort": true,
+	"strconv": true, "strings": true, "sync": true, "syscall": true, "testing": true,
+	"text": true, "time": true, "unicode": true, "unique": true, "unsafe": true,
+	"http": true, "url": true, "rand": true, "sql": true, "signal": true,
+	"filepath": true, "template": true, "atomic": true, "heap": true, "list": true,
+	"ring": true, "csv": true, "xml": true, "base64": true, "hex": true,
+	"binary": true, "pprof": true, "exec": true, "user": true, "zip": true,
+	"gzip": true, "tls": true, "slog": true, "ast": true, "token": true,
+	"parser": true, "format": true, "build": true, "debug": true, "utf8": true,
+}
+
+// reservedImportNames are identifiers already imported by generated app main files.
+var reservedImportNames = map[string]bool{
+	"core": true,
+}
+
+// IsStdlibName reports whether name collides with a standard library package name.
+func IsStdlibName(name string) bool {
+	return stdlibPackages[name]
+}
+
+// ModuleImportName returns the identifier a module is imported as in its app's main file.
+// Names that would shadow a standard library or generated package get a "mod" suffix,
+// so a module named "http" is imported as httpmod while keeping "package http".
+func ModuleImportName(moduleName string) string {
+	if stdlibPackages[moduleName] || reservedImportNames[moduleName] {
+		return moduleName + "mod"
+	}
+	return moduleName
+}
+
+// NameProblems returns what makes name, the directory of an app or module,
+// awkward as the Go package name the templates derive from it. It returns
+// nil for a name that follows Go conventions: one lower-case word that
+// shadows no standard library or generated package.
+func NameProblems(name string) []string {
+	var problems []string
+	if !token.IsIdentifier(name) {
+		problems = append(problems, "is not a valid Go identifier, so the generated code does not compile")
+	}
+	if strings.ToLower(name) != name {
+		problems = append(problems, "has upper-case letters")
+	}
+	if strings.ContainsAny(name, "_-") {
+		problems = append(problems, "separates words with underscores or hyphens")
+	}
+	if stdlibPackages[name] {
+		problems = append(problems, fmt.Sprintf("shadows the standard library package %s", name))
+	}
+	if reservedImportNames[name] {
+		problems = append(problems, fmt.Sprintf("collides with the generated %s package", name))
+	}
+	return problems
+}
+
+// SuggestName returns a conventional replacement for a name NameProblems
+// reports: the name normalized as ModuleNames does, with the "mod" suffix
+// ModuleImportName would import it with if it still collides.
+func SuggestName(name string) string {
+	pkg, _ := ModuleNames(name)
+	pkg = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, pkg)
+	if pkg == "" || !unicode.IsLetter([]rune(pkg)[0]) {
+		pkg = "x" + pkg
+	}
+	if token.IsKeyword(pkg) {
+		return pkg + "mod"
+	}
+	return ModuleImportName(pkg)
+}
+
+// ModuleNames normalizes a module name however it was typed. pkg is the
+// lower-case name used for the package, directory, and files; typ prefixes the
+// module's types. "order-service", "order_service", "orderService", and
+// "OrderService" all give ("orderservice", "OrderService").
+func ModuleNames(name string) (pkg, typ string) {
+	return strings.ToLower(strings.Join(SplitWords(name), "")), GoName(name)
+}
+
+// EnvPrefix returns the environment variable prefix for an app, e.g. "billing-api" -> "BILLING_API".
+func EnvPrefix(appName string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(appName))
+}
+
+// initialisms are words kept fully upper-case in Go identifiers, e.g. UserID, not UserId.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"JWT": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// SplitWords splits an identifier into words at underscores, hyphens, spaces, and
+// case changes, keeping runs of capitals together: "UserID" -> [User ID],
+// "URLPath" -> [URL Path], "user_name" -> [user name].
+func SplitWords(s string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	}) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			lowerToUpper := !unicode.IsUpper(prev) && unicode.IsUpper(cur)
+			endOfRun := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || endOfRun {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// GoName returns the exported Go identifier for s, upper-casing common
+// initialisms: "user_id" -> "UserID", "url" -> "URL".
+func GoName(s string) string {
+	var b strings.Builder
+	for _, w := range SplitWords(s) {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			b.WriteString(capitalize(strings.ToLower(w)))
+		}
+	}
+	return b.String()
+}
+
+// Tag casing styles for generated struct tags.
+const (
+	TagSnake  = "snake"
+	TagCamel  = "camel"
+	TagPascal = "pascal"
+)
+
+// TagName formats a field name for a struct tag in the given style. Initialisms
+// are treated as ordinary words so they never produce forms like "iD":
+// "UserID" is user_id (snake), userId (camel), or UserId (pascal).
+func TagName(field, style string) string {
+	words := SplitWords(field)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	switch style {
+	case TagCamel, TagPascal:
+		for i, w := range words {
+			if i > 0 || style == TagPascal {
+				words[i] = capitalize(w)
+			}
+		}
+		return strings.Join(words, "")
+	default:
+		return strings.Join(words, "_")
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModuleImportName(t *testing.T) {
+	tests := []struct {
+		module, want string
+	}{
+		{"http", "httpmod"},
+		{"context", "contextmod"},
+		{"errors", "errorsmod"},
+		{"time", "timemod"},
+		{"core", "coremod"},
+		{"users", "users"},
+	}
+	for _, tt := range tests {
+		if got := ModuleImportName(tt.module); got != tt.want {
+			t.Errorf("ModuleImportName(%q) = %q, want %q", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestModuleNames(t *testing.T) {
+	tests := []struct {
+		name, pkg, typ string
+	}{
+		{"order", "order", "Order"},
+		{"Order", "order", "Order"},
+		{"orderService", "orderservice", "OrderService"},
+		{"OrderService", "orderservice", "OrderService"},
+		{"order_service", "orderservice", "OrderService"},
+		{"order-service", "orderservice", "OrderService"},
+	}
+	for _, tt := range tests {
+		pkg, typ := ModuleNames(tt.name)
+		if pkg != tt.pkg || typ != tt.typ {
+			t.Errorf("ModuleNames(%q) = (%q, %q), want (%q, %q)", tt.name, pkg, typ, tt.pkg, tt.typ)
+		}
+	}
+}
+
+func TestNameProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"users", nil},
+		{"orders2", nil},
+		{"Users", []string{"has upper-case letters"}},
+		{"order_service", []string{"separates words with underscores or hyphens"}},
+		{"order-service", []string{"is not a valid Go identifier, so the generated code does not compile", "separates words with underscores or hyphens"}},
+		{"2fa", []string{"is not a valid Go identifier, so the generated code does not compile"}},
+		{"http", []string{"shadows the standard library package http"}},
+		{"core", []string{"collides with the generated core package"}},
+	}
+	for _, tt := range tests {
+		got := NameProblems(tt.name)
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Errorf("NameProblems(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"users", "users"},
+		{"Users", "users"},
+		{"order_service", "orderservice"},
+		{"order-service", "orderservice"},
+		{"OrderService", "orderservice"},
+		{"2fa", "x2fa"},
+		{"http", "httpmod"},
+		{"core", "coremod"},
+		{"type", "typemod"},
+	}
+	for _, tt := range tests {
+		got := SuggestName(tt.name)
+		if got != tt.want {
+			t.Errorf("SuggestName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		if problems := NameProblems(got); len(problems) > 0 {
+			t.Errorf("SuggestName(%q) = %q, which %s", tt.name, got, strings.Join(problems, " and "))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Module directory styles, selected with --dir-style or dir_style in .grobrc.
+const (
+	// DirStyleFlat places modules directly under the app: internal/<app>/<module>.
+	DirStyleFlat = "flat"
+	// DirStyleModules groups modules in a subdirectory: internal/<app>/modules/<module>.
+	DirStyleModules = "modules"
+	// DirStyleNested is an alias for DirStyleModules.
+	DirStyleNested = "nested"
+)
+
+// ValidateDirStyle reports an error for an unknown module directory style.
+// The empty string selects the default, DirStyleFlat.
+func ValidateDirStyle(style string) error {
+	switch style {
+	case "", DirStyleFlat, DirStyleModules, DirStyleNested:
+		return nil
+	}
+	return fmt.Errorf("unknown dir style %q: use flat, nested or modules", style)
+}
+
+// moduleRelPath returns the slash-separated path of a module relative to the project root.
+func moduleRelPath(appName, moduleName, style string) string {
+	switch style {
+	case DirStyleModules, DirStyleNested:
+		return path.Join("internal", appName, "modules", moduleName)
+	default:
+		return path.Join("internal", appName, moduleName)
+	}
+}
+
+// ModuleDir returns the directory of a module on disk. Every command that reads
+// or writes module files goes through it so they agree on the layout.
+func ModuleDir(projectRoot, appName, moduleName, style string) string {
+	return filepath.Join(projectRoot, filepath.FromSlash(moduleRelPath(appName, moduleName, style)))
+}
+
+// FindModuleDir returns the directory of an existing module and the style it
+// was created with. It looks in the given style's place first and then in the
+// other one, since a module may have been created with --dir-style.
+func FindModuleDir(projectRoot, appName, moduleName, style string) (string, string, error) {
+	styles := []string{style, DirStyleModules}
+	if style == DirStyleModules || style == DirStyleNested {
+		styles[1] = DirStyleFlat
+	}
+	var firstErr error
+	for _, s := range styles {
+		dir := ModuleDir(projectRoot, appName, moduleName, s)
+		info, err := os.Stat(dir)
+		if err == nil && info.IsDir() {
+			return dir, s, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%s is not a directory", dir)
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", "", firstErr
+}
+
+// ModuleImportPath returns the Go import path of a module.
+func ModuleImportPath(projectName, appName, moduleName, style string) string {
+	return projectName + "/" + moduleRelPath(appName, moduleName, style)
+}
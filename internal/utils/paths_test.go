@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFindModuleDir(t *testing.T) {
+	root := t.TempDir()
+	flat := ModuleDir(root, "api", "users", DirStyleFlat)
+	nested := ModuleDir(root, "api", "orders", DirStyleModules)
+	for _, dir := range []string{flat, nested} {
+		if err := os.MkdirAll(dir, DirMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		module, style, wantDir, wantStyle string
+	}{
+		{"users", DirStyleFlat, flat, DirStyleFlat},
+		{"users", DirStyleModules, flat, DirStyleFlat},
+		{"orders", "", nested, DirStyleModules},
+		{"orders", DirStyleNested, nested, DirStyleNested},
+	}
+	for _, tt := range tests {
+		dir, style, err := FindModuleDir(root, "api", tt.module, tt.style)
+		if err != nil || dir != tt.wantDir || style != tt.wantStyle {
+			t.Errorf("FindModuleDir(%s, %q) = %s, %q, %v; want %s, %q", tt.module, tt.style, dir, style, err, tt.wantDir, tt.wantStyle)
+		}
+	}
+	if _, _, err := FindModuleDir(root, "api", "missing", ""); err == nil {
+		t.Error("FindModuleDir found a missing module")
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// FirstAppPort is the port of a project's first HTTP app; later apps get the
+// next free port after it.
+const FirstAppPort = 8081
+
+// listenAddr matches the ":<port>" listen addresses in app main files, such as
+// port := ":8081".
+var listenAddr = regexp.MustCompile(`^:([0-9]{1,5})$`)
+
+// MainPorts returns the ports an app's main file listens on, in the order they
+// appear: the HTTP port first, then the gRPC port of apps that have one.
+func MainPorts(path string) ([]int, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var ports []int
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		if m := listenAddr.FindStringSubmatch(s); m != nil {
+			port, _ := strconv.Atoi(m[1])
+			ports = append(ports, port)
+		}
+		return true
+	})
+	return ports, nil
+}
+
+// UsedPorts returns the ports the project's apps listen on, read from their
+// internal/<app>/<app>_main.go files, mapped to the app using each. Main files
+// that do not parse are skipped with a warning.
+func UsedPorts(projectRoot string) (map[int]string, error) {
+	paths, err := filepath.Glob(filepath.Join(projectRoot, "internal", "*", "*_main.go"))
+	if err != nil {
+		return nil, err
+	}
+	used := map[int]string{}
+	for _, path := range paths {
+		app := filepath.Base(filepath.Dir(path))
+		if filepath.Base(path) != app+"_main.go" {
+			continue
+		}
+		ports, err := MainPorts(path)
+		if err != nil {
+			// One broken main file must not block creating other apps.
+			if !os.IsNotExist(err) {
+				log.Printf("Warning: skipping the ports of app '%s': %v", app, err)
+			}
+			continue
+		}
+		for _, p := range ports {
+			if _, ok := used[p]; !ok {
+				used[p] = app
+			}
+		}
+	}
+	return used, nil
+}
+
+// NextFreePort returns the first port from FirstAppPort that is neither used
+// nor one of the reserved ports.
+func NextFreePort(used map[int]string, reserved ...int) int {
+	port := FirstAppPort
+	for {
+		free := used[port] == ""
+		for _, r := range reserved {
+			free = free && r != port
+		}
+		if free {
+			return port
+		}
+		port++
+	}
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUsedPortsSkipsBrokenMains(t *testing.T) {
+	root := t.TempDir()
+	mains := map[string]string{
+		"api":  "package api\n\nfunc (a App) Run() {\n\tport := \":8081\"\n\t_ = port\n}\n",
+		"2app": "package 2app\n",
+	}
+	for app, src := range mains {
+		dir := filepath.Join(root, "internal", app)
+		if err := os.MkdirAll(dir, DirMode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, app+"_main.go"), []byte(src), FileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	used, err := UsedPorts(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int]string{8081: "api"}; !reflect.DeepEqual(used, want) {
+		t.Errorf("UsedPorts = %v, want %v", used, want)
+	}
+}
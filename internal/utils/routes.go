@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Route is an HTTP route registered in a RegisterRoutes function.
+type Route struct {
+	Method string
+	// Path is relative to the router group RegisterRoutes is given. Parts that
+	// are not string literals, such as constants, appear as Go expressions in
+	// braces, e.g. "{UserAdminPath}/new".
+	Path string
+	// Handler is the last handler of the route, e.g. "UsersController.List"
+	// for a method of the receiver.
+	Handler string
+}
+
+// routeMethods are the gin router methods that register a route for one HTTP
+// method; Any and Handle are handled separately.
+var routeMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// ParseRoutes returns the routes registered by the RegisterRoutes functions and
+// methods in a file, in source order. Routes on groups created from the router
+// with Group, directly or through a variable, include the group's prefix.
+func ParseRoutes(filePath string) ([]Route, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), filePath, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []Route
+	for _, decl := range node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil || fd.Name.Name != "RegisterRoutes" {
+			continue
+		}
+		params := fd.Type.Params.List
+		if len(params) == 0 || len(params[0].Names) == 0 {
+			continue
+		}
+		receiver, typeName := "", ""
+		if fd.Recv != nil && len(fd.Recv.List[0].Names) > 0 {
+			receiver, typeName = fd.Recv.List[0].Names[0].Name, receiverTypeName(fd.Recv)
+		}
+		groups := map[string]string{params[0].Names[0].Name: ""}
+
+		// prefix returns the path prefix of a router expression: the router,
+		// a group variable, or a router.Group("/x") call.
+		var prefix func(e ast.Expr) (string, bool)
+		prefix = func(e ast.Expr) (string, bool) {
+			switch e := e.(type) {
+			case *ast.Ident:
+				p, ok := groups[e.Name]
+				return p, ok
+			case *ast.CallExpr:
+				sel, ok := e.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "Group" || len(e.Args) == 0 {
+					return "", false
+				}
+				p, ok := prefix(sel.X)
+				return joinRoutePath(p, routePath(e.Args[0])), ok
+			}
+			return "", false
+		}
+
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+					if id, ok := n.Lhs[0].(*ast.Ident); ok {
+						if p, ok := prefix(n.Rhs[0]); ok {
+							groups[id.Name] = p
+						}
+					}
+				}
+			case *ast.CallExpr:
+				sel, ok := n.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				p, ok := prefix(sel.X)
+				if !ok {
+					return true
+				}
+				args := n.Args
+				method := sel.Sel.Name
+				switch {
+				case routeMethods[method]:
+				case method == "Any":
+					method = "ANY"
+				case method == "Handle" && len(args) > 0:
+					method = strings.Trim(types.ExprString(args[0]), `"`)
+					args = args[1:]
+				default:
+					return true
+				}
+				if len(args) < 2 {
+					return true
+				}
+				handler := types.ExprString(args[len(args)-1])
+				if s, ok := args[len(args)-1].(*ast.SelectorExpr); ok && receiver != "" {
+					if id, ok := s.X.(*ast.Ident); ok && id.Name == receiver {
+						handler = typeName + "." + s.Sel.Name
+					}
+				}
+				full := joinRoutePath(p, routePath(args[0]))
+				if full == "" {
+					full = "/"
+				}
+				routes = append(routes, Route{Method: method, Path: full, Handler: handler})
+			}
+			return true
+		})
+	}
+	return routes, nil
+}
+
+// routePath returns the value of a string literal, or the source of any other
+// expression in braces.
+func routePath(e ast.Expr) string {
+	if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		if s, err := strconv.Unquote(lit.Value); err == nil {
+			return s
+		}
+	}
+	return "{" + types.ExprString(e) + "}"
+}
+
+// joinRoutePath joins a group prefix and a relative path the way gin does,
+// keeping a trailing slash of the relative path.
+func joinRoutePath(prefix, rel string) string {
+	if rel == "" {
+		return prefix
+	}
+	joined := path.Join("/", prefix, rel)
+	if strings.HasSuffix(rel, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SoftDeleteField is the model field that marks a row as soft-deleted.
+const SoftDeleteField = "DeletedAt"
+
+// SQLRepository describes a repository generated by 'grob generate sql-repository'.
+type SQLRepository struct {
+	// Receiver is the name of the methods' receiver, e.g. "r".
+	Receiver    string
+	Table       string
+	IDColumn    string
+	IDType      string
+	Placeholder string
+	// HasSoftDelete is set when the repository already has a SoftDelete method.
+	HasSoftDelete bool
+}
+
+var deleteQuery = regexp.MustCompile(`^DELETE FROM ([A-Za-z0-9_."` + "`" + `]+) WHERE ([A-Za-z0-9_]+) = (\$1|\?)$`)
+
+// AddSoftDeleteField adds a DeletedAt *time.Time field to the model struct in
+// path, with a json tag in the given style. It reports whether the field was
+// added; a model that already has it is left unchanged.
+func AddSoftDeleteField(path, modelName, tagStyle string) (bool, error) {
+	field := fmt.Sprintf("%s *time.Time `json:%q`", SoftDeleteField, TagName(SoftDeleteField, tagStyle)+",omitempty")
+	return AddStructField(path, modelName, SoftDeleteField, field, "time")
+}
+
+// AddStructField appends a field, given as source such as "Name string", to the
+// named struct in path, importing importPath if it is not empty. It reports
+// whether the field was added; a struct that already has a field of that name
+// is left unchanged.
+func AddStructField(path, structName, fieldName, field, importPath string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	st, _, err := findStruct(path, src, structName)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range st.Fields.List {
+		for _, name := range f.Names {
+			if name.Name == fieldName {
+				return false, nil
+			}
+		}
+	}
+
+	if importPath != "" {
+		if src, err = addImportSource(path, src, "", importPath); err != nil {
+			return false, err
+		}
+	}
+	// The import moved the struct, so find it again.
+	st, fset, err := findStruct(path, src, structName)
+	if err != nil {
+		return false, err
+	}
+	out, err := insertSource(src, fset.Position(st.Fields.Closing).Offset, field+"\n")
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, out, FileMode)
+}
+
+// findStruct returns the declaration of the named struct type in a file.
+func findStruct(path string, src []byte, name string) (*ast.StructType, *token.FileSet, error) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, decl := range node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok && ts.Name.Name == name {
+				return st, fset, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("struct %s not found in %s", name, path)
+}
+
+// QualifiedTable returns the table as the generated queries name it: as is
+// without a schema, or qualified with the schema and both identifiers quoted
+// for the driver, e.g. "tenant1"."profiles" for postgres and
+// `tenant1`.`profiles` for mysql.
+func QualifiedTable(driver, schema, table string) string {
+	if schema == "" {
+		return table
+	}
+	quote := `"`
+	if driver == "mysql" {
+		quote = "`"
+	}
+	return quote + schema + quote + "." + quote + table + quote
+}
+
+// UnquoteTable returns a table named as QualifiedTable names it without the
+// quotes, e.g. tenant1.profiles, for messages, comments, and file names.
+func UnquoteTable(table string) string {
+	return strings.NewReplacer(`"`, "", "`", "").Replace(table)
+}
+
+// ParseSQLRepository reads the table, key column, key type, and placeholder
+// style of <model>Repository from the query in its Delete method.
+func ParseSQLRepository(path, modelName string) (SQLRepository, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return SQLRepository{}, err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return SQLRepository{}, err
+	}
+
+	typeName := modelName + "Repository"
+	var repo SQLRepository
+	found := false
+	for _, decl := range node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || receiverTypeName(fd.Recv) != typeName {
+			continue
+		}
+		switch fd.Name.Name {
+		case "SoftDelete":
+			repo.HasSoftDelete = true
+		case "Delete":
+			params := fd.Type.Params.List
+			if len(params) != 2 || len(fd.Recv.List[0].Names) == 0 {
+				continue
+			}
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				query, _ := strconv.Unquote(lit.Value)
+				if m := deleteQuery.FindStringSubmatch(query); m != nil {
+					repo.Table, repo.IDColumn, repo.Placeholder = m[1], m[2], m[3]
+					found = true
+				}
+				return true
+			})
+			repo.Receiver = fd.Recv.List[0].Names[0].Name
+			repo.IDType = exprSource(fset, src, node.Comments, params[1].Type)
+		}
+	}
+	if !found {
+		return SQLRepository{}, fmt.Errorf("%s has no Delete method with a generated \"DELETE FROM <table> WHERE <id> = ...\" query", typeName)
+	}
+	return repo, nil
+}
+
+// AddSoftDeleteFilter hides soft-deleted rows from <model>Repository: every
+// SELECT and UPDATE query in its methods gets a "<column> IS NULL" condition,
+// and method, the source of a SoftDelete method, is appended to the file.
+// Queries that already test the column are left alone.
+func AddSoftDeleteFilter(path, modelName, column, method string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	condition := column + " IS NULL"
+	for _, decl := range node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || fd.Body == nil || receiverTypeName(fd.Recv) != modelName+"Repository" {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			query, err := strconv.Unquote(lit.Value)
+			if err != nil || strings.Contains(query, condition) {
+				return true
+			}
+			if !strings.HasPrefix(query, "SELECT ") && !strings.HasPrefix(query, "UPDATE ") {
+				return true
+			}
+			edits = append(edits, edit{
+				start: fset.Position(lit.Pos()).Offset,
+				end:   fset.Position(lit.End()).Offset,
+				text:  strconv.Quote(addCondition(query, condition)),
+			})
+			return true
+		})
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	out = append(out, "\n"+method...)
+	out, err = format.Source(out)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, FileMode)
+}
+
+// addCondition adds a condition to a query's WHERE clause, or gives it one,
+// ahead of any ORDER BY, LIMIT, or RETURNING clause.
+func addCondition(query, condition string) string {
+	tail := len(query)
+	for _, clause := range []string{" ORDER BY ", " LIMIT ", " RETURNING "} {
+		if i := strings.Index(query, clause); i >= 0 && i < tail {
+			tail = i
+		}
+	}
+	keyword := " WHERE "
+	if strings.Contains(query[:tail], " WHERE ") {
+		keyword = " AND "
+	}
+	return query[:tail] + keyword + condition + query[tail:]
+}
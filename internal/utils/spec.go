@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec declares the apps and modules a project should contain.
+type Spec struct {
+	Apps []SpecApp `yaml:"apps"`
+}
+
+// SpecApp declares an app and its modules.
+type SpecApp struct {
+	Name    string   `yaml:"name"`
+	Modules []string `yaml:"modules"`
+}
+
+// LoadSpec reads a project spec file.
+func LoadSpec(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	for i, app := range s.Apps {
+		if app.Name == "" {
+			return nil, fmt.Errorf("invalid spec %s: app %d has no name", path, i+1)
+		}
+	}
+	return &s, nil
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// templateContextLines is how many lines before and after the failing line
+// TemplateError shows.
+const templateContextLines = 2
+
+// TemplateError is a parse or execute error of a user-provided template file,
+// located in that file.
+type TemplateError struct {
+	// File is the template file, as the user gave it.
+	File string
+	// Line is 1-based; Column is 1-based too, or 0 when text/template does not
+	// report it, as for parse errors.
+	Line, Column int
+	Msg          string
+	// Context holds the lines around Line, numbered, with the failing line
+	// marked and a caret under Column.
+	Context string
+	Err     error
+}
+
+func (e *TemplateError) Error() string {
+	pos := fmt.Sprintf("%s:%d", e.File, e.Line)
+	if e.Column > 0 {
+		pos += fmt.Sprintf(":%d", e.Column)
+	}
+	return fmt.Sprintf("%s: %s\n%s", pos, e.Msg, e.Context)
+}
+
+func (e *TemplateError) Unwrap() error { return e.Err }
+
+// newTemplateError locates an error returned by text/template for the template
+// named file, whose source is src. Errors without a position in file are
+// returned unchanged.
+func newTemplateError(file, src string, err error) error {
+	// Parse errors read "template: NAME:LINE: msg", execute errors
+	// "template: NAME:LINE:COL: executing \"NAME\" at <.X>: msg".
+	re := regexp.MustCompile(`^template: ` + regexp.QuoteMeta(file) + `:(\d+)(?::(\d+))?: (?:executing "` + regexp.QuoteMeta(file) + `" )?`)
+	m := re.FindStringSubmatchIndex(err.Error())
+	if m == nil {
+		return err
+	}
+	msg := err.Error()
+	line, _ := strconv.Atoi(msg[m[2]:m[3]])
+	col := 0
+	if m[4] >= 0 {
+		// text/template counts columns from 0.
+		col, _ = strconv.Atoi(msg[m[4]:m[5]])
+		col++
+	}
+	return &TemplateError{
+		File:    file,
+		Line:    line,
+		Column:  col,
+		Msg:     msg[m[1]:],
+		Context: templateContext(src, line, col),
+		Err:     err,
+	}
+}
+
+// templateContext returns the lines of src around line, each prefixed with its
+// number, marking line with ">" and, when col is set, a caret under it.
+func templateContext(src string, line, col int) string {
+	lines := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	first, last := line-templateContextLines, line+templateContextLines
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, lines[n-1])
+		if n == line && col > 0 && col <= len(lines[n-1])+1 {
+			// Keep tabs so the caret lines up with the text above it.
+			indent := strings.Map(func(r rune) rune {
+				if r == '\t' {
+					return r
+				}
+				return ' '
+			}, lines[n-1][:col-1])
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", indent)
+		}
+	}
+	return b.String()
+}
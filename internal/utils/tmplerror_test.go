@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestNewTemplateError(t *testing.T) {
+	tests := []struct {
+		name, src    string
+		data         interface{}
+		line, column int
+		context      string
+	}{
+		{
+			name: "parse error",
+			src:  "package {{.Name}}\n\nfunc {{.Func}() {}\n\nvar x = 1\n",
+			line: 3,
+			context: "  1 | package {{.Name}}\n" +
+				"  2 | \n" +
+				"> 3 | func {{.Func}() {}\n" +
+				"  4 | \n" +
+				"  5 | var x = 1\n",
+		},
+		{
+			name:   "execute error",
+			src:    "a\nb\n\tc {{.Missing.Field}}\nd\ne\nf\n",
+			data:   map[string]interface{}{},
+			line:   3,
+			column: 14,
+			context: "  1 | a\n" +
+				"  2 | b\n" +
+				"> 3 | \tc {{.Missing.Field}}\n" +
+				"    | \t            ^\n" +
+				"  4 | d\n" +
+				"  5 | e\n",
+		},
+	}
+	for _, tt := range tests {
+		tmpl, err := template.New("module.tmpl").Option("missingkey=error").Parse(tt.src)
+		if err == nil {
+			err = tmpl.Execute(&strings.Builder{}, tt.data)
+		}
+		if err == nil {
+			t.Fatalf("%s: template did not fail", tt.name)
+		}
+
+		var te *TemplateError
+		if !errors.As(newTemplateError("module.tmpl", tt.src, err), &te) {
+			t.Errorf("%s: error %v is not located", tt.name, err)
+			continue
+		}
+		if te.Line != tt.line || te.Column != tt.column {
+			t.Errorf("%s: located at %d:%d, want %d:%d", tt.name, te.Line, te.Column, tt.line, tt.column)
+		}
+		if te.Context != tt.context {
+			t.Errorf("%s: context =\n%s\nwant\n%s", tt.name, te.Context, tt.context)
+		}
+		if !errors.Is(te, err) {
+			t.Errorf("%s: TemplateError does not wrap %v", tt.name, err)
+		}
+	}
+}
+
+func TestNewTemplateErrorOtherTemplate(t *testing.T) {
+	err := errors.New(`template: other.tmpl:3: unexpected "}" in operand`)
+	if got := newTemplateError("module.tmpl", "x\n", err); got != err {
+		t.Errorf("newTemplateError located an error of another template: %v", got)
+	}
+}
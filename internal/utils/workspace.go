@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// WorkFileName is the Go workspace file of projects created with 'grob new --workspace'.
+const WorkFileName = "go.work"
+
+// LocalModuleVersion is the version a module requires of another module that
+// is replaced by a directory in the project, as go mod edit would write it.
+const LocalModuleVersion = "v0.0.0-00010101000000-000000000000"
+
+// AddWorkspaceUse adds a use directive for dir, relative to the project root,
+// to the project's go.work unless it is already there.
+func AddWorkspaceUse(projectRoot, dir string) error {
+	path := filepath.Join(projectRoot, WorkFileName)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := modfile.ParseWork(path, b, nil)
+	if err != nil {
+		return err
+	}
+	dir = filepath.ToSlash(dir)
+	for _, u := range f.Use {
+		if u.Path == dir {
+			return nil
+		}
+	}
+	if err := f.AddUse(dir, ""); err != nil {
+		return err
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	return os.WriteFile(path, modfile.Format(f.Syntax), FileMode)
+}
+
+// AddLocalModule makes the project's go.mod require modulePath from dir,
+// relative to the project root, with a replace directive. go.work is enough
+// for builds in the workspace; this keeps go mod tidy and GOWORK=off working.
+func AddLocalModule(projectRoot, modulePath, dir string) error {
+	path := filepath.Join(projectRoot, "go.mod")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := modfile.Parse(path, b, nil)
+	if err != nil {
+		return err
+	}
+	for _, r := range f.Replace {
+		if r.Old.Path == modulePath {
+			return nil
+		}
+	}
+	if err := f.AddRequire(modulePath, LocalModuleVersion); err != nil {
+		return err
+	}
+	if err := f.AddReplace(modulePath, "", filepath.ToSlash(dir), ""); err != nil {
+		return err
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	out, err := f.Format()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, FileMode)
+}
+
+// ModuleRequirement returns the version of modulePath the project's go.mod
+// requires, and the directory or module it is replaced with, if any. Both are
+// empty when the module is not required.
+func ModuleRequirement(projectRoot, modulePath string) (version, replacement string, err error) {
+	path := filepath.Join(projectRoot, "go.mod")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	f, err := modfile.Parse(path, b, nil)
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range f.Require {
+		if r.Mod.Path == modulePath {
+			version = r.Mod.Version
+		}
+	}
+	for _, r := range f.Replace {
+		if r.Old.Path == modulePath {
+			replacement = r.New.Path
+			if r.New.Version != "" {
+				replacement += " " + r.New.Version
+			}
+		}
+	}
+	return version, replacement, nil
+}